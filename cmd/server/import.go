@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/eventimport"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/sqlite"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// dryRun only prints what an import would change
+var dryRun bool
+
+// newImportCommand creates the command importing events from an iCalendar file or feed
+func newImportCommand() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import <file or URL>",
+		Short: "Import events from an iCalendar file or feed",
+		Long: `Import the events of an iCalendar (.ics) file or feed into the SQLite database.
+Events are matched by their UID, so importing a calendar again updates the events
+it added before. Cancelled events are deleted and recurring events are skipped.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImport,
+	}
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print what the import would change")
+	return importCmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	groupTimeZone, err := domain.LoadTimeZone(timeZone)
+	if err != nil {
+		return errors.Wrapf(err, "invalid --timezone %q", timeZone)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+	defer cancel()
+
+	calendar, err := readCalendar(ctx, args[0], groupTimeZone)
+	if err != nil {
+		return err
+	}
+
+	sqliteFactory, err := sqlite.NewRepositoryFactory(dbPath, clock.System{}, groupTimeZone)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize SQLite repositories")
+	}
+	defer sqliteFactory.Close()
+
+	importer := eventimport.NewImporter(sqliteFactory.GetEventRepository(), groupTimeZone)
+	changes, err := importer.Plan(ctx, calendar)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	for _, change := range changes {
+		printChange(out, change)
+	}
+	fmt.Fprintf(out, "\n%d to add, %d to update, %d to delete, %d unchanged, %d skipped\n",
+		eventimport.Count(changes, eventimport.ChangeAdd),
+		eventimport.Count(changes, eventimport.ChangeUpdate),
+		eventimport.Count(changes, eventimport.ChangeDelete),
+		eventimport.Count(changes, eventimport.ChangeUnchanged),
+		eventimport.Count(changes, eventimport.ChangeSkip))
+
+	if dryRun {
+		fmt.Fprintln(out, "Dry run, nothing was changed")
+		return nil
+	}
+
+	if err := importer.Apply(ctx, changes); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Import done")
+	return nil
+}
+
+// readCalendar reads a calendar from a file, or from a feed for http and https URLs
+func readCalendar(ctx context.Context, source string, floating *time.Location) (ical.Calendar, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return ical.Calendar{}, errors.Wrap(err, "invalid calendar URL")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return ical.Calendar{}, errors.Wrap(err, "failed to fetch calendar")
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return ical.Calendar{}, errors.Errorf("failed to fetch calendar: %s", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return ical.Calendar{}, errors.Wrap(err, "failed to open calendar")
+		}
+		r = file
+	}
+	defer r.Close()
+
+	calendar, err := ical.Decode(r, floating)
+	if err != nil {
+		return ical.Calendar{}, errors.Wrap(err, "failed to parse calendar")
+	}
+	return calendar, nil
+}
+
+// printChange prints a change as a line of the import diff, followed by the fields an update changes
+func printChange(out io.Writer, change eventimport.Change) {
+	markers := map[eventimport.ChangeType]string{
+		eventimport.ChangeAdd:       "+",
+		eventimport.ChangeUpdate:    "~",
+		eventimport.ChangeDelete:    "-",
+		eventimport.ChangeUnchanged: "=",
+		eventimport.ChangeSkip:      "!",
+	}
+
+	line := fmt.Sprintf("%s %-9s %q", markers[change.Type], change.Type, change.Event.Title)
+	if change.Type == eventimport.ChangeAdd {
+		line += " on " + change.Event.LocalDate().Format("Mon, Jan 2, 2006 15:04 MST")
+	}
+	if change.Reason != "" {
+		line += ": " + change.Reason
+	}
+	fmt.Fprintln(out, line)
+
+	for _, field := range change.Fields {
+		fmt.Fprintf(out, "    %s: %q -> %q\n", field.Field, shorten(field.From), shorten(field.To))
+	}
+}
+
+// shorten cuts long values like descriptions down to a line of the diff
+func shorten(value string) string {
+	const maxLength = 60
+	runes := []rune(strings.ReplaceAll(value, "\n", " "))
+	if len(runes) <= maxLength {
+		return string(runes)
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
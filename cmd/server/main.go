@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Embed the timezone database so event timezones work on hosts without one
+	_ "time/tzdata"
 
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/handlers"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
@@ -24,6 +29,12 @@ var (
 	useSQLite  bool
 	dbPath     string
 	serverPort int
+	hostKey    string
+	timeZone   string
+
+	questionsPerMinute float64
+	questionBurst      int
+	trustedProxies     []string
 )
 
 func main() {
@@ -36,8 +47,15 @@ func main() {
 
 	// Add flags
 	rootCmd.Flags().BoolVar(&useSQLite, "sqlite", false, "Use SQLite repositories instead of mock repositories")
-	rootCmd.Flags().StringVar(&dbPath, "db-path", "ai-in-action.db", "Path to SQLite database file (only used with --sqlite)")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", "ai-in-action.db", "Path to SQLite database file (only used with --sqlite)")
 	rootCmd.Flags().IntVar(&serverPort, "port", 8080, "Port to run the server on")
+	rootCmd.PersistentFlags().StringVar(&timeZone, "timezone", "UTC", "IANA timezone of the group, like Europe/Berlin, that new events are scheduled in by default")
+	rootCmd.Flags().StringVar(&hostKey, "host-key", "", "Key the host opens /host/questions?key=... with to moderate questions (a random one is generated and logged without one)")
+	rootCmd.Flags().Float64Var(&questionsPerMinute, "questions-per-minute", 2, "Questions one client (session cookie and IP) can submit per minute, 0 to turn the limit off")
+	rootCmd.Flags().IntVar(&questionBurst, "question-burst", 3, "Questions one client can submit in a row before the per-minute limit applies")
+	rootCmd.Flags().StringSliceVar(&trustedProxies, "trusted-proxies", nil, "IP ranges (CIDR) of reverse proxies whose X-Forwarded-For header gives the client address; without any the connection's address is used")
+
+	rootCmd.AddCommand(newImportCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -47,43 +65,76 @@ func main() {
 
 func runServer(cmd *cobra.Command, args []string) error {
 	var (
-		eventRepo     repository.EventRepository
-		timerRepo     repository.TimerRepository
-		noteRepo      repository.NoteRepository
-		questionRepo  repository.QuestionRepository
+		repos         repository.Repositories
 		sqliteFactory *sqlite.RepositoryFactory
 		err           error
 	)
 
+	groupTimeZone, err := domain.LoadTimeZone(timeZone)
+	if err != nil {
+		return errors.Wrapf(err, "invalid --timezone %q", timeZone)
+	}
+
 	// Initialize repositories based on flag
 	if useSQLite {
 		log.Printf("Using SQLite repositories with database: %s\n", dbPath)
-		sqliteFactory, err = sqlite.NewRepositoryFactory(dbPath)
+		sqliteFactory, err = sqlite.NewRepositoryFactory(dbPath, clock.System{}, groupTimeZone)
 		if err != nil {
 			return errors.Wrap(err, "failed to initialize SQLite repositories")
 		}
 		defer sqliteFactory.Close()
 
-		eventRepo = sqliteFactory.GetEventRepository()
-		timerRepo = sqliteFactory.GetTimerRepository()
-		noteRepo = sqliteFactory.GetNoteRepository()
-		questionRepo = sqliteFactory.GetQuestionRepository()
+		repos = sqliteFactory.Repositories()
 	} else {
 		log.Println("Using mock repositories")
-		eventRepo = mock.NewMockEventRepository()
-		timerRepo = mock.NewMockTimerRepository()
-		noteRepo = mock.NewMockNoteRepository()
-		questionRepo = mock.NewMockQuestionRepository()
+		mockEventRepo := mock.NewMockEventRepository(clock.System{}, groupTimeZone)
+		repos = repository.Repositories{
+			Events:    mockEventRepo,
+			Speakers:  mock.NewMockSpeakerRepository(mockEventRepo),
+			Resources: mock.NewMockResourceRepository(),
+			Series:    mock.NewMockSeriesRepository(),
+			Timers:    mock.NewMockTimerRepository(),
+			TimerLogs: mock.NewMockTimerLogRepository(),
+			Agendas:   mock.NewMockAgendaRepository(),
+			Notes:     mock.NewMockNoteRepository(),
+			Questions: mock.NewMockQuestionRepository(),
+		}
 	}
 
 	// Initialize Echo
 	e := echo.New()
+	e.IPExtractor, err = newIPExtractor(trustedProxies)
+	if err != nil {
+		return err
+	}
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Static("/static", "static")
 
+	// Background workers and long-lived streams (like Server-Sent Events) are
+	// stopped when the server shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	e.Server.BaseContext = func(net.Listener) context.Context {
+		return workerCtx
+	}
+
+	if hostKey == "" {
+		hostKey, err = handlers.NewHostKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to generate host key")
+		}
+		log.Printf("No --host-key given, moderate questions at /host/questions?key=%s\n", hostKey)
+	}
+
 	// Register handlers
-	handlers.RegisterHandlers(e, eventRepo, timerRepo, noteRepo, questionRepo)
+	handlers.RegisterHandlers(workerCtx, e, repos, handlers.Config{
+		HostKey:            hostKey,
+		QuestionsPerMinute: questionsPerMinute,
+		QuestionBurst:      questionBurst,
+		Clock:              clock.System{},
+		TimeZone:           groupTimeZone,
+	})
 
 	// Start server in a goroutine
 	go func() {
@@ -101,6 +152,7 @@ func runServer(cmd *cobra.Command, args []string) error {
 	<-quit
 
 	// Graceful shutdown
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := e.Shutdown(ctx); err != nil {
@@ -109,3 +161,23 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// newIPExtractor returns how client addresses are read: from the X-Forwarded-For header set by
+// the given proxies, or from the connection when no proxy is trusted, so clients can't pick
+// their own address
+func newIPExtractor(proxies []string) (echo.IPExtractor, error) {
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Only the given ranges are trusted, not echo's default of all private addresses
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range proxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid --trusted-proxies range %q", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
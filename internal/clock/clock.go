@@ -0,0 +1,25 @@
+// Package clock abstracts the current time, so code that depends on it can run at a pinned time
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock of the machine the server runs on
+type System struct{}
+
+// Now returns the current system time
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed is a clock that always tells the same time
+type Fixed time.Time
+
+// Now returns the pinned time
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// CurrentSegment returns the segment the agenda is at
+func (a Agenda) CurrentSegment() (AgendaSegment, bool) {
+	if a.CurrentIndex < 0 || a.CurrentIndex >= len(a.Segments) {
+		return AgendaSegment{}, false
+	}
+	return a.Segments[a.CurrentIndex], true
+}
+
+// HasNext reports whether there is a segment after the current one
+func (a Agenda) HasNext() bool {
+	return a.CurrentIndex+1 < len(a.Segments)
+}
+
+// TotalDuration returns the planned duration of all segments together
+func (a Agenda) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, segment := range a.Segments {
+		total += segment.Duration
+	}
+	return total
+}
+
+// ErrNoNextSegment is returned when advancing an agenda that is at its last segment
+var ErrNoNextSegment = errors.New("agenda has no next segment")
+
+// Advance moves the agenda to the next segment
+func (a *Agenda) Advance() error {
+	if !a.HasNext() {
+		return ErrNoNextSegment
+	}
+	a.CurrentIndex++
+	return nil
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EventLength is how long an event without a duration is considered to be running after it starts
+const EventLength = 2 * time.Hour
+
+// calendarUIDFormat is the iCalendar UID of events added in the app, derived from the event ID
+const calendarUIDFormat = "event-%d@ai-in-action"
+
+// CalendarUID returns the iCalendar UID of the event: the one it was imported with, or one
+// derived from its ID. It stays the same when the event changes, so calendar apps that
+// imported the event update it instead of adding it again.
+func (e Event) CalendarUID() string {
+	if e.UID != "" {
+		return e.UID
+	}
+	return fmt.Sprintf(calendarUIDFormat, e.ID)
+}
+
+// EventIDFromCalendarUID returns the ID of the event a UID made by CalendarUID was derived from.
+// It returns false for UIDs of events imported from other calendars.
+func EventIDFromCalendarUID(uid string) (uint, bool) {
+	var id uint
+	if _, err := fmt.Sscanf(uid, calendarUIDFormat, &id); err != nil || fmt.Sprintf(calendarUIDFormat, id) != uid {
+		return 0, false
+	}
+	return id, true
+}
+
+// LoadTimeZone loads a timezone by its IANA name, like "Europe/Berlin" or "UTC".
+// Unlike time.LoadLocation it rejects "Local" and the empty name, whose meaning depends on the server.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// Location returns the timezone the event is scheduled in, UTC if it has none or an unknown one
+func (e Event) Location() *time.Location {
+	location, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// LocalDate returns the start of the event in the timezone it is scheduled in
+func (e Event) LocalDate() time.Time {
+	return e.Date.In(e.Location())
+}
+
+// Length returns how long the event takes, EventLength for events without a duration
+func (e Event) Length() time.Duration {
+	if e.Duration > 0 {
+		return e.Duration
+	}
+	return EventLength
+}
+
+// End returns when the event ends
+func (e Event) End() time.Time {
+	return e.Date.Add(e.Length())
+}
+
+// IsRunning reports whether the event has started and hasn't ended yet at now
+func (e Event) IsRunning(now time.Time) bool {
+	return !now.Before(e.Date) && now.Before(e.End())
+}
+
+// IsOver reports whether the event has ended at now.
+// Events that haven't ended yet count as upcoming.
+func (e Event) IsOver(now time.Time) bool {
+	return !now.Before(e.End())
+}
+
+// CurrentEvent picks the event attendees most likely mean at now: the running event
+// that started last, otherwise the next event to start, otherwise the last event that ran.
+// It returns false if there are no events.
+func CurrentEvent(events []Event, now time.Time) (Event, bool) {
+	if len(events) == 0 {
+		return Event{}, false
+	}
+
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].IsRunning(now) {
+			return sorted[i], true
+		}
+	}
+
+	for _, event := range sorted {
+		if event.Date.After(now) {
+			return event, true
+		}
+	}
+
+	return sorted[len(sorted)-1], true
+}
@@ -4,38 +4,133 @@ import (
 	"time"
 )
 
-// Event represents a talk or workshop in the AI in Action group
+// Event represents a talk or workshop in the AI in Action group.
+// Date is when the event starts; TimeZone is the IANA name of the timezone it is scheduled in,
+// like "Europe/Berlin", which decides the wall clock time it was announced with.
+// Sequence counts the updates of the event and UpdatedAt is when it last changed, so calendar
+// apps that imported the event can tell it changed. UID is the iCalendar UID of an event
+// imported from another calendar, empty for events added in the app.
+// Events generated by a Series keep its SeriesID and the Occurrence they were generated for,
+// even when moved. They are Open slots until a speaker claims them.
+// Speakers are the people giving the event, in the order they are named, and Speaker is
+// the line of their names shown with the event and used by notes, search and calendars.
 type Event struct {
 	ID          uint
 	Title       string
 	Speaker     string
+	Speakers    []Speaker
 	Description string
 	Date        time.Time
-	IsUpcoming  bool
+	TimeZone    string
+	// Duration is how long the event takes, EventLength if zero
+	Duration   time.Duration
+	Sequence   int
+	UpdatedAt  time.Time
+	UID        string
+	SeriesID   uint
+	Occurrence time.Time
+	Open       bool
 }
 
-// Timer represents a countdown timer for talks
+// Timer represents a countdown timer for talks.
+// Timers belong to an event and are optionally split into segments like "talk" and "Q&A".
+// The timer with no event and no segment is the default timer.
+// State changes go through the transition methods in timer.go.
 type Timer struct {
-	ID            uint
-	Duration      time.Duration
+	ID       uint
+	EventID  uint
+	Segment  string
+	Duration time.Duration
+	// RemainingTime is the time left as of LastStartedAt
 	RemainingTime time.Duration
-	IsRunning     bool
+	// Overtime is how far the timer has run past zero as of LastStartedAt
+	Overtime      time.Duration
+	State         TimerState
 	LastStartedAt time.Time
+	// WarningThresholds are the remaining times at which the host is warned, largest first
+	WarningThresholds []time.Duration
+	// Version is incremented on every write and used for optimistic concurrency
+	Version int64
+}
+
+// TimerEventType identifies the kind of change that happened to a timer
+type TimerEventType string
+
+const (
+	TimerEventStart  TimerEventType = "start"
+	TimerEventPause  TimerEventType = "pause"
+	TimerEventResume TimerEventType = "resume"
+	TimerEventReset  TimerEventType = "reset"
+	TimerEventAdjust TimerEventType = "adjust"
+	TimerEventConfig TimerEventType = "config"
+	TimerEventTick   TimerEventType = "tick"
+	TimerEventExpire TimerEventType = "expire"
+	// TimerEventAdvance is published with the timer of the segment an agenda moved to
+	TimerEventAdvance TimerEventType = "advance"
+)
+
+// TimerEvent describes a timer state change and the resulting timer state
+type TimerEvent struct {
+	Type       TimerEventType
+	Timer      Timer
+	OccurredAt time.Time
+}
+
+// TimerLogEntry is a recorded timer event, kept so the actual length of a talk can be reported later
+type TimerLogEntry struct {
+	ID      uint
+	TimerID uint
+	EventID uint
+	Segment string
+	Type    TimerEventType
+	// Duration, RemainingTime and Overtime are the timer's values right after the event
+	Duration      time.Duration
+	RemainingTime time.Duration
+	Overtime      time.Duration
+	OccurredAt    time.Time
+}
+
+// Agenda is the ordered list of segments of an event, like intro, demo, talk and Q&A.
+// Each segment is timed by the event timer with the same segment name.
+type Agenda struct {
+	ID           uint
+	EventID      uint
+	Segments     []AgendaSegment
+	CurrentIndex int
+	// AutoAdvance moves to the next segment as soon as the current one runs out of time
+	AutoAdvance bool
 }
 
-// Note represents speaker notes for a talk
+// AgendaSegment is a single timed part of an agenda
+type AgendaSegment struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Note represents speaker notes for a talk.
+// Notes belong to an event and optionally to one of its speakers; EventID 0 holds general notes.
 type Note struct {
 	ID         uint
+	EventID    uint
+	Speaker    string
 	Content    string
 	PageNumber int
 	TotalPages int
 }
 
-// Question represents a question submitted by an attendee
+// Question represents a question submitted by an attendee.
+// Questions belong to an event; EventID 0 holds questions asked outside of any event.
 type Question struct {
-	ID          uint
+	ID      uint
+	EventID uint
+	// Name is empty for questions asked anonymously
 	Name        string
 	Content     string
 	SubmittedAt time.Time
-	Answered    bool
-} 
\ No newline at end of file
+	// State is where the question is in the moderation workflow
+	State QuestionState
+	// MergedInto is the question a merged duplicate was folded into, 0 otherwise
+	MergedInto uint
+	// Votes is the number of attendees who upvoted the question
+	Votes int
+}
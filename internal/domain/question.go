@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// QuestionState is where a question is in the host's moderation workflow
+type QuestionState string
+
+const (
+	// QuestionStatePending is a question waiting in the queue
+	QuestionStatePending QuestionState = "pending"
+	// QuestionStatePinned is the question the host is answering now, at most one per event
+	QuestionStatePinned QuestionState = "pinned"
+	// QuestionStateAnswered is a question the host has answered
+	QuestionStateAnswered QuestionState = "answered"
+	// QuestionStateHidden is a question the host hid from attendees, like spam
+	QuestionStateHidden QuestionState = "hidden"
+	// QuestionStateMerged is a duplicate folded into another question, see MergedInto
+	QuestionStateMerged QuestionState = "merged"
+)
+
+// ErrInvalidQuestionTransition is returned when a moderation action isn't allowed in the question's current state
+var ErrInvalidQuestionTransition = errors.New("invalid question transition")
+
+// DisplayName returns the name shown with the question, "Anonymous" for questions asked without one
+func (q Question) DisplayName() string {
+	if q.Name == "" {
+		return "Anonymous"
+	}
+	return q.Name
+}
+
+// IsOpen reports whether the question still waits for an answer and takes votes
+func (q Question) IsOpen() bool {
+	return q.State == QuestionStatePending || q.State == QuestionStatePinned
+}
+
+// IsVisible reports whether attendees see the question
+func (q Question) IsVisible() bool {
+	return q.IsOpen() || q.State == QuestionStateAnswered
+}
+
+// Pin marks a pending question as the one being answered now
+func (q *Question) Pin() error {
+	if q.State != QuestionStatePending {
+		return q.invalidTransition("pin")
+	}
+	q.State = QuestionStatePinned
+	return nil
+}
+
+// Answer marks a pending or pinned question as answered
+func (q *Question) Answer() error {
+	if !q.IsOpen() {
+		return q.invalidTransition("answer")
+	}
+	q.State = QuestionStateAnswered
+	return nil
+}
+
+// Hide hides a visible question from attendees
+func (q *Question) Hide() error {
+	if !q.IsVisible() {
+		return q.invalidTransition("hide")
+	}
+	q.State = QuestionStateHidden
+	return nil
+}
+
+// Restore puts a hidden question back into the queue
+func (q *Question) Restore() error {
+	if q.State != QuestionStateHidden {
+		return q.invalidTransition("restore")
+	}
+	q.State = QuestionStatePending
+	return nil
+}
+
+// Edit replaces the name and content of a question, for example to fix typos.
+// Merged questions can't be edited, as they are no longer shown anywhere.
+func (q *Question) Edit(name string, content string) error {
+	if q.State == QuestionStateMerged {
+		return q.invalidTransition("edit")
+	}
+	q.Name = name
+	q.Content = content
+	return nil
+}
+
+// MergeInto folds a pending or hidden duplicate into another visible question of the same event
+func (q *Question) MergeInto(target Question) error {
+	if q.State != QuestionStatePending && q.State != QuestionStateHidden {
+		return q.invalidTransition("merge")
+	}
+	if target.ID == q.ID || target.EventID != q.EventID || !target.IsVisible() {
+		return fmt.Errorf("cannot merge question %d into question %d: %w", q.ID, target.ID, ErrInvalidQuestionTransition)
+	}
+	q.State = QuestionStateMerged
+	q.MergedInto = target.ID
+	return nil
+}
+
+// invalidTransition returns the error for an action that isn't allowed in the question's current state
+func (q Question) invalidTransition(action string) error {
+	return fmt.Errorf("cannot %s a question that is %s: %w", action, q.State, ErrInvalidQuestionTransition)
+}
+
+// QuestionEventType identifies the kind of change that happened to a question
+type QuestionEventType string
+
+const (
+	QuestionEventAdd     QuestionEventType = "add"
+	QuestionEventVote    QuestionEventType = "vote"
+	QuestionEventAnswer  QuestionEventType = "answer"
+	QuestionEventPin     QuestionEventType = "pin"
+	QuestionEventHide    QuestionEventType = "hide"
+	QuestionEventRestore QuestionEventType = "restore"
+	QuestionEventEdit    QuestionEventType = "edit"
+	QuestionEventMerge   QuestionEventType = "merge"
+)
+
+// QuestionEvent describes a change to a question of an event and the resulting question
+type QuestionEvent struct {
+	Type       QuestionEventType
+	Question   Question
+	OccurredAt time.Time
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// SegmentTiming compares the planned and actual length of one timer of an event
+type SegmentTiming struct {
+	TimerID uint
+	Segment string
+	// Planned is the timer's duration when it was first started, or its latest duration if it never was
+	Planned time.Duration
+	// Actual is how long the timer was counting, including overtime
+	Actual time.Duration
+	// InProgress is set while the timer is still counting, in which case Actual runs up to now
+	InProgress bool
+}
+
+// Overrun returns how much longer the segment took than planned, negative if it was shorter
+func (s SegmentTiming) Overrun() time.Duration {
+	return s.Actual - s.Planned
+}
+
+// EventTiming compares the planned and actual length of all timed segments of an event
+type EventTiming struct {
+	Event    Event
+	Segments []SegmentTiming
+	Planned  time.Duration
+	Actual   time.Duration
+}
+
+// Overrun returns how much longer the event took than planned, negative if it was shorter
+func (e EventTiming) Overrun() time.Duration {
+	return e.Actual - e.Planned
+}
+
+// NewEventTiming computes the timing of an event from the log entries of its timers.
+// A timer counts from a start, resume or advance until the next pause or reset;
+// expiring doesn't stop it as it keeps counting into overtime.
+// Segments are ordered by when their timer was first used.
+func NewEventTiming(event Event, entries []TimerLogEntry, now time.Time) EventTiming {
+	entries = append([]TimerLogEntry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt.Before(entries[j].OccurredAt)
+	})
+
+	timing := EventTiming{Event: event}
+	index := make(map[uint]int)
+	runningSince := make(map[uint]time.Time)
+	started := make(map[uint]bool)
+	for _, entry := range entries {
+		i, exists := index[entry.TimerID]
+		if !exists {
+			i = len(timing.Segments)
+			index[entry.TimerID] = i
+			timing.Segments = append(timing.Segments, SegmentTiming{
+				TimerID: entry.TimerID,
+				Segment: entry.Segment,
+			})
+		}
+		segment := &timing.Segments[i]
+
+		startedAt, running := runningSince[entry.TimerID]
+		switch entry.Type {
+		case TimerEventStart, TimerEventResume, TimerEventAdvance:
+			if !running {
+				runningSince[entry.TimerID] = entry.OccurredAt
+			}
+			if !started[entry.TimerID] {
+				started[entry.TimerID] = true
+				segment.Planned = entry.Duration
+			}
+		case TimerEventPause, TimerEventReset:
+			if running {
+				segment.Actual += entry.OccurredAt.Sub(startedAt)
+				delete(runningSince, entry.TimerID)
+			}
+		}
+		if !started[entry.TimerID] {
+			segment.Planned = entry.Duration
+		}
+	}
+
+	for id, startedAt := range runningSince {
+		segment := &timing.Segments[index[id]]
+		segment.Actual += now.Sub(startedAt)
+		segment.InProgress = true
+	}
+
+	for _, segment := range timing.Segments {
+		timing.Planned += segment.Planned
+		timing.Actual += segment.Actual
+	}
+
+	return timing
+}
+
+// IsLogged reports whether events of this type are kept in the timer log.
+// Ticks and warning configuration changes don't affect how long a talk took.
+func (t TimerEventType) IsLogged() bool {
+	return t != TimerEventTick && t != TimerEventConfig
+}
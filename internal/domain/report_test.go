@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// logEntry returns a timer log entry of type eventType for a timer with the given duration,
+// logged the given offset after timerStart
+func logEntry(timerID uint, segment string, eventType TimerEventType, duration time.Duration, offset time.Duration) TimerLogEntry {
+	return TimerLogEntry{
+		TimerID:    timerID,
+		EventID:    1,
+		Segment:    segment,
+		Type:       eventType,
+		Duration:   duration,
+		OccurredAt: at(offset),
+	}
+}
+
+func TestNewEventTiming(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []TimerLogEntry
+		now      time.Time
+		segments []SegmentTiming
+	}{
+		{
+			name: "start, pause and resume",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 10*time.Minute, 0),
+				logEntry(1, "talk", TimerEventPause, 10*time.Minute, 5*time.Minute),
+				logEntry(1, "talk", TimerEventResume, 10*time.Minute, 7*time.Minute),
+				logEntry(1, "talk", TimerEventPause, 10*time.Minute, 10*time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 10 * time.Minute, Actual: 8 * time.Minute},
+			},
+		},
+		{
+			name: "expiry keeps counting into overtime",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 10*time.Minute, 0),
+				logEntry(1, "talk", TimerEventExpire, 10*time.Minute, 10*time.Minute),
+				logEntry(1, "talk", TimerEventPause, 10*time.Minute, 12*time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 10 * time.Minute, Actual: 12 * time.Minute},
+			},
+		},
+		{
+			name: "advance to the next segment",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 20*time.Minute, 0),
+				logEntry(1, "talk", TimerEventPause, 20*time.Minute, 22*time.Minute),
+				logEntry(2, "Q&A", TimerEventAdvance, 10*time.Minute, 22*time.Minute),
+				logEntry(2, "Q&A", TimerEventPause, 10*time.Minute, 30*time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 20 * time.Minute, Actual: 22 * time.Minute},
+				{TimerID: 2, Segment: "Q&A", Planned: 10 * time.Minute, Actual: 8 * time.Minute},
+			},
+		},
+		{
+			name: "reset while paused keeps the planned duration",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 10*time.Minute, 0),
+				logEntry(1, "talk", TimerEventPause, 10*time.Minute, 5*time.Minute),
+				logEntry(1, "talk", TimerEventReset, 15*time.Minute, 6*time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 10 * time.Minute, Actual: 5 * time.Minute},
+			},
+		},
+		{
+			name: "reset while running stops counting",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 10*time.Minute, 0),
+				logEntry(1, "talk", TimerEventReset, 10*time.Minute, 4*time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 10 * time.Minute, Actual: 4 * time.Minute},
+			},
+		},
+		{
+			name: "never started timer plans its latest duration",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventReset, 10*time.Minute, 0),
+				logEntry(1, "talk", TimerEventReset, 15*time.Minute, time.Minute),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 15 * time.Minute},
+			},
+		},
+		{
+			name: "segment in progress counts up to now",
+			entries: []TimerLogEntry{
+				logEntry(1, "talk", TimerEventStart, 20*time.Minute, 0),
+				logEntry(1, "talk", TimerEventPause, 20*time.Minute, 18*time.Minute),
+				logEntry(2, "Q&A", TimerEventAdvance, 10*time.Minute, 18*time.Minute),
+			},
+			now: at(21 * time.Minute),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 20 * time.Minute, Actual: 18 * time.Minute},
+				{TimerID: 2, Segment: "Q&A", Planned: 10 * time.Minute, Actual: 3 * time.Minute, InProgress: true},
+			},
+		},
+		{
+			name: "entries out of order",
+			entries: []TimerLogEntry{
+				logEntry(2, "Q&A", TimerEventPause, 10*time.Minute, 30*time.Minute),
+				logEntry(1, "talk", TimerEventPause, 20*time.Minute, 22*time.Minute),
+				logEntry(2, "Q&A", TimerEventAdvance, 10*time.Minute, 22*time.Minute),
+				logEntry(1, "talk", TimerEventStart, 20*time.Minute, 0),
+			},
+			now: at(time.Hour),
+			segments: []SegmentTiming{
+				{TimerID: 1, Segment: "talk", Planned: 20 * time.Minute, Actual: 22 * time.Minute},
+				{TimerID: 2, Segment: "Q&A", Planned: 10 * time.Minute, Actual: 8 * time.Minute},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timing := NewEventTiming(Event{ID: 1}, tt.entries, tt.now)
+
+			if !reflect.DeepEqual(timing.Segments, tt.segments) {
+				t.Fatalf("segments: got %+v, want %+v", timing.Segments, tt.segments)
+			}
+
+			var planned, actual time.Duration
+			for _, segment := range tt.segments {
+				planned += segment.Planned
+				actual += segment.Actual
+			}
+			if timing.Planned != planned || timing.Actual != actual {
+				t.Fatalf("event: got planned %s and actual %s, want %s and %s", timing.Planned, timing.Actual, planned, actual)
+			}
+		})
+	}
+}
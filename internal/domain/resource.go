@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+)
+
+// ResourceKind is what kind of material a resource is
+type ResourceKind string
+
+const (
+	ResourceKindSlides  ResourceKind = "slides"
+	ResourceKindCode    ResourceKind = "code"
+	ResourceKindPaper   ResourceKind = "paper"
+	ResourceKindArticle ResourceKind = "article"
+	ResourceKindVideo   ResourceKind = "video"
+	// ResourceKindLink is any other link
+	ResourceKindLink ResourceKind = "link"
+)
+
+// ResourceKinds are the kinds of resources, in the order the add form offers them
+var ResourceKinds = []ResourceKind{
+	ResourceKindSlides,
+	ResourceKindCode,
+	ResourceKindPaper,
+	ResourceKindArticle,
+	ResourceKindVideo,
+	ResourceKindLink,
+}
+
+// Label returns the name of the kind shown to people, like "Slides"
+func (k ResourceKind) Label() string {
+	switch k {
+	case ResourceKindSlides:
+		return "Slides"
+	case ResourceKindCode:
+		return "Code"
+	case ResourceKindPaper:
+		return "Paper"
+	case ResourceKindArticle:
+		return "Article"
+	case ResourceKindVideo:
+		return "Video"
+	default:
+		return "Link"
+	}
+}
+
+// ParseResourceKind returns the kind named s, and false if there is no such kind
+func ParseResourceKind(s string) (ResourceKind, bool) {
+	for _, kind := range ResourceKinds {
+		if string(kind) == s {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// Resource is material attached to a talk, like its slides, the repository of its demo
+// or a paper it discusses. Resources belong to an event and are listed on its page.
+type Resource struct {
+	ID      uint
+	EventID uint
+	Kind    ResourceKind
+	Title   string
+	URL     string
+	// Notes say what the resource is about, in Markdown
+	Notes   string
+	AddedAt time.Time
+}
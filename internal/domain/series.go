@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Series is a recurring session of the group, like the weekly meetup. It repeats every
+// Interval weeks on the weekday and at the time of day of its First occurrence, in its TimeZone,
+// until Until if that is set. The series generates open event slots for its coming
+// occurrences, which speakers claim to give their talk.
+type Series struct {
+	ID       uint
+	Title    string
+	First    time.Time
+	TimeZone string
+	// Interval is the number of weeks between sessions
+	Interval int
+	// Duration is how long each session takes
+	Duration time.Duration
+	Until    time.Time
+}
+
+// Location returns the timezone the series is scheduled in, UTC if it has none or an unknown one
+func (s Series) Location() *time.Location {
+	return Event{TimeZone: s.TimeZone}.Location()
+}
+
+// Occurrences returns the starts of the sessions that start in [from, to).
+// Sessions keep their time of day across daylight saving time changes.
+func (s Series) Occurrences(from time.Time, to time.Time) []time.Time {
+	interval := s.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	first := s.First.In(s.Location())
+
+	// Skip whole intervals before from instead of stepping through them one by one
+	step := 0
+	if from.After(first) {
+		step = int(from.Sub(first)/(time.Duration(interval)*7*24*time.Hour)) - 1
+		if step < 0 {
+			step = 0
+		}
+	}
+
+	var occurrences []time.Time
+	for ; ; step++ {
+		occurrence := first.AddDate(0, 0, 7*interval*step)
+		if !occurrence.Before(to) || (!s.Until.IsZero() && occurrence.After(s.Until)) {
+			return occurrences
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+}
+
+// Slots returns the open event slots of the sessions that start in [from, to)
+func (s Series) Slots(from time.Time, to time.Time) []Event {
+	occurrences := s.Occurrences(from, to)
+	slots := make([]Event, len(occurrences))
+	for i, occurrence := range occurrences {
+		slots[i] = Event{
+			Title:      s.Title,
+			Date:       occurrence,
+			TimeZone:   s.TimeZone,
+			Duration:   s.Duration,
+			SeriesID:   s.ID,
+			Occurrence: occurrence,
+			Open:       true,
+		}
+	}
+	return slots
+}
+
+// RRule returns the recurrence of the series as an iCalendar RRULE, like FREQ=WEEKLY;INTERVAL=1;BYDAY=TH
+func (s Series) RRule() string {
+	interval := s.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	weekday := strings.ToUpper(s.First.In(s.Location()).Weekday().String()[:2])
+
+	rule := fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d;BYDAY=%s", interval, weekday)
+	if !s.Until.IsZero() {
+		rule += ";UNTIL=" + s.Until.UTC().Format("20060102T150405Z")
+	}
+	return rule
+}
+
+// Describe returns how often the series meets, like "Every Thursday at 18:00 (Europe/Berlin)"
+func (s Series) Describe() string {
+	first := s.First.In(s.Location())
+	every := "Every"
+	if s.Interval > 1 {
+		every = fmt.Sprintf("Every %d weeks on", s.Interval)
+	}
+
+	description := fmt.Sprintf("%s %s at %s (%s)", every, first.Weekday(), first.Format("15:04"), s.TimeZone)
+	if !s.Until.IsZero() {
+		description += ", until " + s.Until.In(s.Location()).Format("Jan 2, 2006")
+	}
+	return description
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeriesOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+
+	// evening returns 19:00 in Berlin on a day
+	evening := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 19, 0, 0, 0, berlin)
+	}
+	weekly := Series{First: evening(2025, 3, 6), TimeZone: "Europe/Berlin", Interval: 1}
+
+	tests := []struct {
+		name   string
+		series func() Series
+		from   time.Time
+		to     time.Time
+		want   []time.Time
+	}{
+		{
+			name:   "from the first session",
+			series: func() Series { return weekly },
+			from:   evening(2025, 3, 6),
+			to:     evening(2025, 3, 20),
+			want:   []time.Time{evening(2025, 3, 6), evening(2025, 3, 13)},
+		},
+		{
+			name:   "keeps the time of day across daylight saving time",
+			series: func() Series { return weekly },
+			from:   evening(2025, 3, 21),
+			to:     evening(2025, 4, 10),
+			want:   []time.Time{evening(2025, 3, 27), evening(2025, 4, 3)},
+		},
+		{
+			name:   "nothing before the first session",
+			series: func() Series { return weekly },
+			from:   evening(2025, 2, 1),
+			to:     evening(2025, 3, 6),
+			want:   nil,
+		},
+		{
+			name:   "long after the first session",
+			series: func() Series { return weekly },
+			from:   evening(2026, 11, 1),
+			to:     evening(2026, 11, 13),
+			want:   []time.Time{evening(2026, 11, 5), evening(2026, 11, 12)},
+		},
+		{
+			name: "every other week",
+			series: func() Series {
+				series := weekly
+				series.Interval = 2
+				return series
+			},
+			from: evening(2025, 3, 7),
+			to:   evening(2025, 4, 30),
+			want: []time.Time{evening(2025, 3, 20), evening(2025, 4, 3), evening(2025, 4, 17)},
+		},
+		{
+			name: "until the last session",
+			series: func() Series {
+				series := weekly
+				series.Until = evening(2025, 3, 20)
+				return series
+			},
+			from: evening(2025, 3, 1),
+			to:   evening(2025, 5, 1),
+			want: []time.Time{evening(2025, 3, 6), evening(2025, 3, 13), evening(2025, 3, 20)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.series().Occurrences(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) || got[i].Location().String() != "Europe/Berlin" {
+					t.Errorf("Occurrences()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSeriesRRuleAndSlots(t *testing.T) {
+	series := Series{
+		First:    time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC),
+		TimeZone: "UTC",
+		Interval: 2,
+		Until:    time.Date(2025, 6, 26, 18, 0, 0, 0, time.UTC),
+	}
+
+	want := "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH;UNTIL=20250626T180000Z"
+	if got := series.RRule(); got != want {
+		t.Errorf("RRule() = %q, want %q", got, want)
+	}
+
+	slots := series.Slots(series.First, series.First.AddDate(0, 0, 15))
+	dates := make([]time.Time, len(slots))
+	for i, slot := range slots {
+		if !slot.Open || slot.Occurrence != slot.Date {
+			t.Errorf("slot %d = %+v, want an open slot at its occurrence", i, slot)
+		}
+		dates[i] = slot.Date
+	}
+	if want := []time.Time{series.First, series.First.AddDate(0, 0, 14)}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("slot dates = %v, want %v", dates, want)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+)
+
+// Speaker is a person giving talks. Events link to their speakers, so a speaker's profile
+// lists every talk they gave or will give, including the ones they co-presented.
+// Names are unique, ignoring case, and are how the event form refers to speakers.
+type Speaker struct {
+	ID   uint
+	Name string
+	Bio  string
+	// Links are URLs of the speaker's website, profiles or repositories
+	Links []string
+	// AvatarURL is the URL of the speaker's picture, empty to show their initials
+	AvatarURL string
+}
+
+// Initials returns the first letters of up to two words of the speaker's name, shown without an avatar
+func (s Speaker) Initials() string {
+	var initials []rune
+	for _, word := range strings.Fields(s.Name) {
+		initials = append(initials, []rune(strings.ToUpper(word))[0])
+		if len(initials) == 2 {
+			break
+		}
+	}
+	return string(initials)
+}
+
+// SpeakerList returns the speakers of the event, parsed from its speaker line when they aren't set
+func (e Event) SpeakerList() []Speaker {
+	if len(e.Speakers) > 0 {
+		return e.Speakers
+	}
+	return ParseSpeakers(e.Speaker)
+}
+
+// speakerSeparator separates the names of co-presenters in the speaker line of an event
+const speakerSeparator = ", "
+
+// SpeakerLine joins the names of speakers into the speaker line of an event, like "Ada Lovelace, Alan Turing"
+func SpeakerLine(speakers []Speaker) string {
+	names := make([]string, len(speakers))
+	for i, speaker := range speakers {
+		names[i] = speaker.Name
+	}
+	return strings.Join(names, speakerSeparator)
+}
+
+// ParseSpeakers splits a speaker line into speakers that only have a name. Co-presenters are
+// separated by commas or ampersands, as in "Ada Lovelace & Alan Turing"; empty and repeated
+// names are dropped.
+func ParseSpeakers(line string) []Speaker {
+	return SpeakersNamed(strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == '&'
+	}))
+}
+
+// SpeakersNamed returns speakers that only have a name for the given names, trimmed.
+// Empty names and names repeated ignoring case are dropped.
+func SpeakersNamed(names []string) []Speaker {
+	var speakers []Speaker
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		speakers = append(speakers, Speaker{Name: name})
+	}
+	return speakers
+}
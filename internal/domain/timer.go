@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimerState is the state of a countdown timer
+type TimerState string
+
+const (
+	// TimerStateIdle is a timer that has been reset and not started yet
+	TimerStateIdle TimerState = "idle"
+	// TimerStateRunning is a timer counting down with time left
+	TimerStateRunning TimerState = "running"
+	// TimerStatePaused is a timer that was stopped with time left
+	TimerStatePaused TimerState = "paused"
+	// TimerStateExpired is a timer that was stopped after running out of time
+	TimerStateExpired TimerState = "expired"
+	// TimerStateOvertime is a timer that keeps counting after running out of time
+	TimerStateOvertime TimerState = "overtime"
+)
+
+// DefaultWarningThresholds are the remaining times at which new timers warn the host
+var DefaultWarningThresholds = []time.Duration{5 * time.Minute, time.Minute}
+
+// ErrInvalidTimerTransition is returned when a transition isn't allowed in the timer's current state
+var ErrInvalidTimerTransition = errors.New("invalid timer transition")
+
+// NewTimer returns an idle timer with the given duration and the default warning thresholds
+func NewTimer(duration time.Duration) Timer {
+	timer := Timer{
+		WarningThresholds: append([]time.Duration(nil), DefaultWarningThresholds...),
+	}
+	timer.Reset(duration)
+	return timer
+}
+
+// IsRunning reports whether the timer is counting, either down or into overtime
+func (t Timer) IsRunning() bool {
+	return t.State == TimerStateRunning || t.State == TimerStateOvertime
+}
+
+// At returns the timer as it is at the given time.
+// For a counting timer the elapsed time is taken off RemainingTime (and added to Overtime
+// once that reaches zero) and LastStartedAt moves to now, so the result can be persisted as is.
+func (t Timer) At(now time.Time) Timer {
+	if !t.IsRunning() {
+		return t
+	}
+
+	t.setBalance(t.balance() - now.Sub(t.LastStartedAt))
+	t.LastStartedAt = now
+	t.State = countingState(t.RemainingTime)
+	return t
+}
+
+// Start starts an idle timer, or restarts an expired timer from its full duration
+func (t *Timer) Start(now time.Time) error {
+	switch t.State {
+	case TimerStateIdle:
+	case TimerStateExpired:
+		t.RemainingTime = t.Duration
+		t.Overtime = 0
+	default:
+		return t.invalidTransition("start")
+	}
+
+	t.State = countingState(t.RemainingTime)
+	t.LastStartedAt = now
+	return nil
+}
+
+// Pause stops a counting timer. A timer paused in overtime becomes expired.
+func (t *Timer) Pause(now time.Time) error {
+	if !t.IsRunning() {
+		return t.invalidTransition("pause")
+	}
+
+	*t = t.At(now)
+	if t.State == TimerStateOvertime {
+		t.State = TimerStateExpired
+	} else {
+		t.State = TimerStatePaused
+	}
+	return nil
+}
+
+// Resume continues a paused timer
+func (t *Timer) Resume(now time.Time) error {
+	if t.State != TimerStatePaused {
+		return t.invalidTransition("resume")
+	}
+
+	t.State = TimerStateRunning
+	t.LastStartedAt = now
+	return nil
+}
+
+// Reset stops the timer and sets it to a new duration. It is allowed in every state.
+func (t *Timer) Reset(duration time.Duration) {
+	t.Duration = duration
+	t.RemainingTime = duration
+	t.Overtime = 0
+	t.State = TimerStateIdle
+}
+
+// Adjust adds delta (which may be negative) to the remaining time.
+// Added time first pays back any overtime, and taking away more than is left runs into overtime.
+// The duration grows with the remaining time so an idle timer keeps starting from its full duration.
+func (t *Timer) Adjust(delta time.Duration, now time.Time) error {
+	adjusted := t.At(now)
+	adjusted.setBalance(adjusted.balance() + delta)
+
+	switch adjusted.State {
+	case TimerStateIdle:
+		if adjusted.RemainingTime <= 0 {
+			return fmt.Errorf("cannot shorten an idle timer to nothing: %w", ErrInvalidTimerTransition)
+		}
+	case TimerStateRunning, TimerStateOvertime:
+		adjusted.State = countingState(adjusted.RemainingTime)
+	case TimerStatePaused, TimerStateExpired:
+		if adjusted.RemainingTime > 0 {
+			adjusted.State = TimerStatePaused
+		} else {
+			adjusted.State = TimerStateExpired
+		}
+	}
+
+	if adjusted.State == TimerStateIdle || adjusted.RemainingTime > adjusted.Duration {
+		adjusted.Duration = adjusted.RemainingTime
+	}
+
+	*t = adjusted
+	return nil
+}
+
+// SetWarningThresholds replaces the warning thresholds. They are kept largest first without duplicates.
+func (t *Timer) SetWarningThresholds(thresholds []time.Duration) error {
+	sorted := make([]time.Duration, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		if threshold <= 0 {
+			return fmt.Errorf("warning threshold %s must be positive", threshold)
+		}
+		sorted = append(sorted, threshold)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] > sorted[j]
+	})
+
+	t.WarningThresholds = sorted[:0]
+	for _, threshold := range sorted {
+		if len(t.WarningThresholds) == 0 || t.WarningThresholds[len(t.WarningThresholds)-1] != threshold {
+			t.WarningThresholds = append(t.WarningThresholds, threshold)
+		}
+	}
+	return nil
+}
+
+// WarningLevel returns how many warning thresholds a started timer has crossed.
+// It is 0 before the first threshold and len(WarningThresholds) after the last one.
+func (t Timer) WarningLevel() int {
+	if t.State == TimerStateIdle {
+		return 0
+	}
+
+	level := 0
+	for _, threshold := range t.WarningThresholds {
+		if t.RemainingTime <= threshold {
+			level++
+		}
+	}
+	return level
+}
+
+// CrossedThreshold returns the most recently crossed warning threshold, if any
+func (t Timer) CrossedThreshold() (time.Duration, bool) {
+	level := t.WarningLevel()
+	if level == 0 {
+		return 0, false
+	}
+	return t.WarningThresholds[level-1], true
+}
+
+// invalidTransition builds the error for an action that isn't allowed in the current state
+func (t Timer) invalidTransition(action string) error {
+	return fmt.Errorf("cannot %s a timer that is %s: %w", action, t.State, ErrInvalidTimerTransition)
+}
+
+// balance returns the remaining time minus the overtime
+func (t Timer) balance() time.Duration {
+	return t.RemainingTime - t.Overtime
+}
+
+// setBalance splits a balance into remaining time and overtime
+func (t *Timer) setBalance(balance time.Duration) {
+	if balance > 0 {
+		t.RemainingTime = balance
+		t.Overtime = 0
+	} else {
+		t.RemainingTime = 0
+		t.Overtime = -balance
+	}
+}
+
+// countingState returns the state of a counting timer with the given remaining time
+func countingState(remaining time.Duration) TimerState {
+	if remaining <= 0 {
+		return TimerStateOvertime
+	}
+	return TimerStateRunning
+}
@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// timerStart is the time the timer tests start their timers at
+var timerStart = time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC)
+
+// at returns the time the given offset after timerStart
+func at(offset time.Duration) time.Time {
+	return timerStart.Add(offset)
+}
+
+func TestTimerTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     func(timer *Timer) error
+		now       time.Time
+		state     TimerState
+		remaining time.Duration
+		overtime  time.Duration
+	}{
+		{
+			name:      "new timer is idle",
+			steps:     func(timer *Timer) error { return nil },
+			now:       at(time.Minute),
+			state:     TimerStateIdle,
+			remaining: 10 * time.Minute,
+		},
+		{
+			name:      "running timer counts down",
+			steps:     func(timer *Timer) error { return timer.Start(at(0)) },
+			now:       at(3 * time.Minute),
+			state:     TimerStateRunning,
+			remaining: 7 * time.Minute,
+		},
+		{
+			name:     "running timer runs into overtime",
+			steps:    func(timer *Timer) error { return timer.Start(at(0)) },
+			now:      at(12 * time.Minute),
+			state:    TimerStateOvertime,
+			overtime: 2 * time.Minute,
+		},
+		{
+			name: "paused timer keeps its remaining time",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				return timer.Pause(at(4 * time.Minute))
+			},
+			now:       at(8 * time.Minute),
+			state:     TimerStatePaused,
+			remaining: 6 * time.Minute,
+		},
+		{
+			name: "resumed timer counts down from where it was paused",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				if err := timer.Pause(at(4 * time.Minute)); err != nil {
+					return err
+				}
+				return timer.Resume(at(8 * time.Minute))
+			},
+			now:       at(9 * time.Minute),
+			state:     TimerStateRunning,
+			remaining: 5 * time.Minute,
+		},
+		{
+			name: "timer paused in overtime expires",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				return timer.Pause(at(11 * time.Minute))
+			},
+			now:      at(20 * time.Minute),
+			state:    TimerStateExpired,
+			overtime: time.Minute,
+		},
+		{
+			name: "expired timer restarts from its full duration",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				if err := timer.Pause(at(11 * time.Minute)); err != nil {
+					return err
+				}
+				return timer.Start(at(20 * time.Minute))
+			},
+			now:       at(21 * time.Minute),
+			state:     TimerStateRunning,
+			remaining: 9 * time.Minute,
+		},
+		{
+			name: "reset timer is idle with the new duration",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				timer.Reset(5 * time.Minute)
+				return nil
+			},
+			now:       at(12 * time.Minute),
+			state:     TimerStateIdle,
+			remaining: 5 * time.Minute,
+		},
+		{
+			name: "added time pays back overtime first",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				return timer.Adjust(5*time.Minute, at(12*time.Minute))
+			},
+			now:       at(12 * time.Minute),
+			state:     TimerStateRunning,
+			remaining: 3 * time.Minute,
+		},
+		{
+			name: "taking away more than is left runs into overtime",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				return timer.Adjust(-12*time.Minute, at(time.Minute))
+			},
+			now:      at(2 * time.Minute),
+			state:    TimerStateOvertime,
+			overtime: 4 * time.Minute,
+		},
+		{
+			name: "time added to an expired timer pauses it",
+			steps: func(timer *Timer) error {
+				if err := timer.Start(at(0)); err != nil {
+					return err
+				}
+				if err := timer.Pause(at(11 * time.Minute)); err != nil {
+					return err
+				}
+				return timer.Adjust(3*time.Minute, at(15*time.Minute))
+			},
+			now:       at(20 * time.Minute),
+			state:     TimerStatePaused,
+			remaining: 2 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := NewTimer(10 * time.Minute)
+			if err := tt.steps(&timer); err != nil {
+				t.Fatalf("steps failed: %v", err)
+			}
+
+			got := timer.At(tt.now)
+			if got.State != tt.state {
+				t.Errorf("State = %q, want %q", got.State, tt.state)
+			}
+			if got.RemainingTime != tt.remaining {
+				t.Errorf("RemainingTime = %s, want %s", got.RemainingTime, tt.remaining)
+			}
+			if got.Overtime != tt.overtime {
+				t.Errorf("Overtime = %s, want %s", got.Overtime, tt.overtime)
+			}
+		})
+	}
+}
+
+func TestTimerInvalidTransitions(t *testing.T) {
+	running := NewTimer(10 * time.Minute)
+	if err := running.Start(at(0)); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	paused := running
+	if err := paused.Pause(at(time.Minute)); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		timer  Timer
+		action func(timer *Timer) error
+	}{
+		{"start a running timer", running, func(timer *Timer) error { return timer.Start(at(2 * time.Minute)) }},
+		{"start a paused timer", paused, func(timer *Timer) error { return timer.Start(at(2 * time.Minute)) }},
+		{"pause an idle timer", NewTimer(time.Minute), func(timer *Timer) error { return timer.Pause(at(0)) }},
+		{"pause a paused timer", paused, func(timer *Timer) error { return timer.Pause(at(2 * time.Minute)) }},
+		{"resume a running timer", running, func(timer *Timer) error { return timer.Resume(at(2 * time.Minute)) }},
+		{"resume an idle timer", NewTimer(time.Minute), func(timer *Timer) error { return timer.Resume(at(0)) }},
+		{"shorten an idle timer to nothing", NewTimer(time.Minute), func(timer *Timer) error { return timer.Adjust(-time.Minute, at(0)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := tt.timer
+			before := timer
+			err := tt.action(&timer)
+			if !errors.Is(err, ErrInvalidTimerTransition) {
+				t.Fatalf("error = %v, want ErrInvalidTimerTransition", err)
+			}
+			if timer.State != before.State || timer.RemainingTime != before.RemainingTime {
+				t.Errorf("timer changed to %q with %s left, want %q with %s left",
+					timer.State, timer.RemainingTime, before.State, before.RemainingTime)
+			}
+		})
+	}
+}
+
+func TestTimerWarningLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		elapsed   time.Duration
+		level     int
+		threshold time.Duration
+		crossed   bool
+	}{
+		{"before the first threshold", 4 * time.Minute, 0, 0, false},
+		{"at the first threshold", 5 * time.Minute, 1, 5 * time.Minute, true},
+		{"past the last threshold", 9*time.Minute + 30*time.Second, 2, time.Minute, true},
+		{"in overtime", 15 * time.Minute, 2, time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := NewTimer(10 * time.Minute)
+			if err := timer.Start(at(0)); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+
+			timer = timer.At(at(tt.elapsed))
+			if level := timer.WarningLevel(); level != tt.level {
+				t.Errorf("WarningLevel() = %d, want %d", level, tt.level)
+			}
+			threshold, crossed := timer.CrossedThreshold()
+			if threshold != tt.threshold || crossed != tt.crossed {
+				t.Errorf("CrossedThreshold() = %s, %v, want %s, %v", threshold, crossed, tt.threshold, tt.crossed)
+			}
+		})
+	}
+}
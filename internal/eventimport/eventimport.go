@@ -0,0 +1,250 @@
+// Package eventimport brings the events of an iCalendar file into the timeline.
+// Events are matched by their iCalendar UID, so importing the same calendar again
+// updates the events it added before instead of adding them twice.
+package eventimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// ChangeType is what importing a calendar event does to the timeline
+type ChangeType string
+
+// Change types
+const (
+	ChangeAdd       ChangeType = "add"
+	ChangeUpdate    ChangeType = "update"
+	ChangeUnchanged ChangeType = "unchanged"
+	ChangeDelete    ChangeType = "delete"
+	ChangeSkip      ChangeType = "skip"
+)
+
+// dateFormat is how dates are shown in the changes of an update
+const dateFormat = "Mon, Jan 2, 2006 15:04 MST"
+
+// FieldChange is a field of an event an update changes, with its value before and after
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Change is what importing one calendar event does.
+// Event is the event as it is after the import, or the stored event for deletes.
+// Skipped calendar events only have the Title and UID they came with.
+type Change struct {
+	Type   ChangeType
+	Event  domain.Event
+	Fields []FieldChange
+	// Reason tells why an event is skipped or deleted
+	Reason string
+}
+
+// Importer plans and applies imports into an event repository
+type Importer struct {
+	eventRepo repository.EventRepository
+	// timeZone is the group's timezone, given to events whose start has none
+	timeZone *time.Location
+}
+
+// NewImporter creates a new importer. Events given in UTC or without a timezone are
+// scheduled in timeZone.
+func NewImporter(eventRepo repository.EventRepository, timeZone *time.Location) *Importer {
+	return &Importer{
+		eventRepo: eventRepo,
+		timeZone:  timeZone,
+	}
+}
+
+// Plan works out what importing the calendar would change without changing anything.
+// This is the dry run of an import; Apply carries the changes out.
+func (i *Importer) Plan(ctx context.Context, calendar ical.Calendar) ([]Change, error) {
+	changes := make([]Change, 0, len(calendar.Events))
+	seen := make(map[string]bool)
+	for _, calendarEvent := range calendar.Events {
+		event := i.convert(calendarEvent)
+
+		if reason := skipReason(calendarEvent, seen); reason != "" {
+			changes = append(changes, Change{Type: ChangeSkip, Event: event, Reason: reason})
+			continue
+		}
+		seen[calendarEvent.UID] = true
+
+		existing, err := i.existingEvent(ctx, calendarEvent.UID)
+		if errors.Is(err, repository.ErrNotFound) {
+			if calendarEvent.Status == "CANCELLED" {
+				changes = append(changes, Change{Type: ChangeSkip, Event: event, Reason: "cancelled"})
+			} else {
+				changes = append(changes, Change{Type: ChangeAdd, Event: event})
+			}
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to look up event %q: %w", calendarEvent.UID, err)
+		}
+
+		if calendarEvent.Status == "CANCELLED" {
+			changes = append(changes, Change{Type: ChangeDelete, Event: existing, Reason: "cancelled"})
+			continue
+		}
+
+		event.ID = existing.ID
+		event.UID = existing.UID
+		if calendarEvent.TimeZone == "" {
+			// Times in UTC say nothing about the timezone the event is scheduled in
+			event.TimeZone = existing.TimeZone
+		}
+		fields := diff(existing, event)
+		if len(fields) == 0 {
+			changes = append(changes, Change{Type: ChangeUnchanged, Event: existing})
+		} else {
+			changes = append(changes, Change{Type: ChangeUpdate, Event: event, Fields: fields})
+		}
+	}
+
+	return changes, nil
+}
+
+// Apply carries out planned changes, stopping at the first one that fails.
+// Updating or deleting an event that was deleted since the changes were planned fails with ErrNotFound.
+func (i *Importer) Apply(ctx context.Context, changes []Change) error {
+	for _, change := range changes {
+		found := true
+		var err error
+		switch change.Type {
+		case ChangeAdd:
+			_, err = i.eventRepo.AddEvent(ctx, change.Event)
+		case ChangeUpdate:
+			found, err = i.eventRepo.UpdateEvent(ctx, change.Event)
+		case ChangeDelete:
+			found, err = i.eventRepo.DeleteEvent(ctx, change.Event.ID)
+		}
+		if err == nil && !found {
+			err = fmt.Errorf("event %d: %w", change.Event.ID, repository.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s event %q: %w", change.Type, change.Event.Title, err)
+		}
+	}
+
+	return nil
+}
+
+// existingEvent returns the event a calendar event was imported as before, or the event
+// of the app itself the calendar event was exported from
+func (i *Importer) existingEvent(ctx context.Context, uid string) (domain.Event, error) {
+	event, err := i.eventRepo.GetEventByUID(ctx, uid)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return event, err
+	}
+
+	id, ok := domain.EventIDFromCalendarUID(uid)
+	if !ok {
+		return domain.Event{}, err
+	}
+	event, err = i.eventRepo.GetEvent(ctx, id)
+	if err == nil && event.UID != "" {
+		// The event was imported from elsewhere, so it isn't the one the UID was derived from
+		return domain.Event{}, fmt.Errorf("event %q: %w", uid, repository.ErrNotFound)
+	}
+	return event, err
+}
+
+// Count returns how many of the changes are of a type
+func Count(changes []Change, changeType ChangeType) int {
+	count := 0
+	for _, change := range changes {
+		if change.Type == changeType {
+			count++
+		}
+	}
+	return count
+}
+
+// convert turns a calendar event into an event of the timeline
+func (i *Importer) convert(calendarEvent ical.Event) domain.Event {
+	timeZone := calendarEvent.TimeZone
+	if timeZone == "" {
+		timeZone = i.timeZone.String()
+	}
+
+	speaker, description := splitSpeaker(calendarEvent.Description)
+	if speaker == "" {
+		speaker = calendarEvent.Organizer
+	}
+	// Stored events have the speaker line of their speakers, so imports are compared by that
+	speakers := domain.ParseSpeakers(speaker)
+
+	var duration time.Duration
+	if calendarEvent.End.After(calendarEvent.Start) {
+		duration = calendarEvent.End.Sub(calendarEvent.Start)
+	}
+
+	return domain.Event{
+		Title:       strings.TrimSpace(calendarEvent.Summary),
+		Speaker:     domain.SpeakerLine(speakers),
+		Speakers:    speakers,
+		Description: description,
+		Date:        calendarEvent.Start,
+		TimeZone:    timeZone,
+		Duration:    duration,
+		UID:         calendarEvent.UID,
+	}
+}
+
+// skipReason tells why a calendar event can't be imported, or returns an empty string if it can
+func skipReason(calendarEvent ical.Event, seen map[string]bool) string {
+	switch {
+	case calendarEvent.UID == "":
+		return "no UID to recognize it by when importing again"
+	case seen[calendarEvent.UID]:
+		return "its UID appears more than once"
+	case calendarEvent.RecurrenceRule != "":
+		return "recurring events aren't imported"
+	case calendarEvent.Start.IsZero():
+		return "no start time"
+	case strings.TrimSpace(calendarEvent.Summary) == "":
+		return "no title"
+	}
+	return ""
+}
+
+// splitSpeaker takes the speaker out of a description starting with a "Speaker: " line,
+// which is how the app's own calendar feed writes it
+func splitSpeaker(description string) (string, string) {
+	first, rest, _ := strings.Cut(description, "\n")
+	speaker, found := strings.CutPrefix(first, "Speaker: ")
+	if !found {
+		return "", strings.TrimSpace(description)
+	}
+	return strings.TrimSpace(speaker), strings.TrimSpace(rest)
+}
+
+// diff returns the fields an update changes
+func diff(from domain.Event, to domain.Event) []FieldChange {
+	var fields []FieldChange
+	add := func(field string, before string, after string) {
+		if before != after {
+			fields = append(fields, FieldChange{Field: field, From: before, To: after})
+		}
+	}
+
+	add("title", from.Title, to.Title)
+	add("speaker", from.Speaker, to.Speaker)
+	add("description", from.Description, to.Description)
+	if !from.Date.Equal(to.Date) {
+		add("date", from.LocalDate().Format(dateFormat), to.LocalDate().Format(dateFormat))
+	}
+	add("timezone", from.TimeZone, to.TimeZone)
+	// Events without a duration are exported with the default length, which is no change
+	add("duration", from.Length().String(), to.Length().String())
+
+	return fields
+}
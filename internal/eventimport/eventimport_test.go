@@ -0,0 +1,176 @@
+package eventimport
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/pkg/errors"
+)
+
+// exported returns an event of the app as its calendar feed writes it
+func exported(event domain.Event) ical.Event {
+	return ical.Event{
+		UID:         event.CalendarUID(),
+		Start:       event.Date,
+		End:         event.End(),
+		Summary:     event.Title,
+		Description: "Speaker: " + event.Speaker + "\n\n" + event.Description,
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC)
+	eventRepo := mock.NewMockEventRepository(clock.Fixed(now), time.UTC)
+	importer := NewImporter(eventRepo, time.UTC)
+
+	upcoming, err := eventRepo.GetUpcomingEvents(ctx)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents failed: %v", err)
+	}
+	kept, cancelled := upcoming[0], upcoming[1]
+
+	imported, err := eventRepo.AddEvent(ctx, domain.Event{
+		Title:    "Agents in Production",
+		Speaker:  "Robin Weiss",
+		Date:     now.AddDate(0, 0, 21),
+		TimeZone: "Europe/Berlin",
+		Duration: time.Hour,
+		UID:      "agents@meetup.example",
+	})
+	if err != nil {
+		t.Fatalf("AddEvent failed: %v", err)
+	}
+
+	moved := ical.Event{
+		UID:         imported.UID,
+		Start:       imported.Date.Add(30 * time.Minute),
+		End:         imported.Date.Add(90 * time.Minute),
+		Summary:     "Agents in Production, Part 2",
+		Description: "Speaker: Robin Weiss",
+	}
+	cancellation := exported(cancelled)
+	cancellation.Status = "CANCELLED"
+	added := ical.Event{
+		UID:       "evals@meetup.example",
+		Start:     now.AddDate(0, 0, 28),
+		End:       now.AddDate(0, 0, 28).Add(45 * time.Minute),
+		Summary:   "Evals that Matter",
+		Organizer: "Kim Lee",
+	}
+
+	calendar := ical.Calendar{Events: []ical.Event{
+		exported(kept),
+		moved,
+		cancellation,
+		added,
+		{Start: now, Summary: "No UID"},
+		{UID: added.UID, Start: now, Summary: "Evals again"},
+		{UID: "weekly@meetup.example", Start: now, Summary: "Weekly", RecurrenceRule: "FREQ=WEEKLY"},
+	}}
+
+	changes, err := importer.Plan(ctx, calendar)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	types := make([]ChangeType, 0, len(changes))
+	for _, change := range changes {
+		types = append(types, change.Type)
+	}
+	wantTypes := []ChangeType{ChangeUnchanged, ChangeUpdate, ChangeDelete, ChangeAdd, ChangeSkip, ChangeSkip, ChangeSkip}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("change types = %v, want %v", types, wantTypes)
+	}
+
+	wantFields := []FieldChange{
+		{Field: "title", From: "Agents in Production", To: "Agents in Production, Part 2"},
+		{Field: "date", From: "Thu, Mar 27, 2025 13:00 CET", To: "Thu, Mar 27, 2025 13:30 CET"},
+	}
+	if !reflect.DeepEqual(changes[1].Fields, wantFields) {
+		t.Errorf("update fields = %+v, want %+v", changes[1].Fields, wantFields)
+	}
+	if changes[1].Event.TimeZone != "Europe/Berlin" {
+		t.Errorf("updated TimeZone = %q, want the stored %q", changes[1].Event.TimeZone, "Europe/Berlin")
+	}
+	if changes[2].Event.ID != cancelled.ID {
+		t.Errorf("deleted event ID = %d, want %d", changes[2].Event.ID, cancelled.ID)
+	}
+	if changes[3].Event.Speaker != "Kim Lee" || changes[3].Event.Duration != 45*time.Minute {
+		t.Errorf("added event = %q for %s, want %q for 45m0s", changes[3].Event.Speaker, changes[3].Event.Duration, "Kim Lee")
+	}
+	if Count(changes, ChangeSkip) != 3 {
+		t.Errorf("Count(skip) = %d, want 3", Count(changes, ChangeSkip))
+	}
+
+	// The dry run leaves the timeline alone
+	if _, err := eventRepo.GetEventByUID(ctx, added.UID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetEventByUID of the planned event = %v, want ErrNotFound", err)
+	}
+	if stored, err := eventRepo.GetEvent(ctx, imported.ID); err != nil || stored.Title != imported.Title {
+		t.Errorf("GetEvent of the planned update = %q, %v, want %q", stored.Title, err, imported.Title)
+	}
+
+	if err := importer.Apply(ctx, changes); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if _, err := eventRepo.GetEventByUID(ctx, added.UID); err != nil {
+		t.Errorf("GetEventByUID of the added event failed: %v", err)
+	}
+	if stored, err := eventRepo.GetEvent(ctx, imported.ID); err != nil || stored.Title != moved.Summary {
+		t.Errorf("GetEvent of the updated event = %q, %v, want %q", stored.Title, err, moved.Summary)
+	}
+	if _, err := eventRepo.GetEvent(ctx, cancelled.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetEvent of the cancelled event = %v, want ErrNotFound", err)
+	}
+
+	// Importing the same calendar again changes nothing more
+	again, err := importer.Plan(ctx, calendar)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	for _, changeType := range []ChangeType{ChangeAdd, ChangeUpdate, ChangeDelete} {
+		if count := Count(again, changeType); count != 0 {
+			t.Errorf("Count(%s) after applying = %d, want 0", changeType, count)
+		}
+	}
+}
+
+func TestApplyToDeletedEvent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC)
+	eventRepo := mock.NewMockEventRepository(clock.Fixed(now), time.UTC)
+	importer := NewImporter(eventRepo, time.UTC)
+
+	upcoming, err := eventRepo.GetUpcomingEvents(ctx)
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents failed: %v", err)
+	}
+	updated, cancelled := exported(upcoming[0]), exported(upcoming[1])
+	updated.Summary = "Renamed"
+	cancelled.Status = "CANCELLED"
+
+	for _, calendarEvent := range []ical.Event{updated, cancelled} {
+		changes, err := importer.Plan(ctx, ical.Calendar{Events: []ical.Event{calendarEvent}})
+		if err != nil {
+			t.Fatalf("Plan failed: %v", err)
+		}
+
+		// The event is deleted between the dry run and the import
+		if _, err := eventRepo.DeleteEvent(ctx, changes[0].Event.ID); err != nil {
+			t.Fatalf("DeleteEvent failed: %v", err)
+		}
+
+		if err := importer.Apply(ctx, changes); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Apply of the %s change to a deleted event = %v, want ErrNotFound", changes[0].Type, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// AgendaHandler handles agenda-related requests
+type AgendaHandler struct {
+	agendaRepo   repository.AgendaRepository
+	timerRepo    repository.TimerRepository
+	timerLogRepo repository.TimerLogRepository
+	timerHub     *pubsub.Hub[domain.TimerEvent]
+}
+
+// NewAgendaHandler creates a new agenda handler
+func NewAgendaHandler(agendaRepo repository.AgendaRepository, timerRepo repository.TimerRepository, timerLogRepo repository.TimerLogRepository, timerHub *pubsub.Hub[domain.TimerEvent]) *AgendaHandler {
+	return &AgendaHandler{
+		agendaRepo:   agendaRepo,
+		timerRepo:    timerRepo,
+		timerLogRepo: timerLogRepo,
+		timerHub:     timerHub,
+	}
+}
+
+// RegisterRoutes registers the agenda routes
+func (h *AgendaHandler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/agenda", h.HandleSaveAgenda)
+	e.POST("/agenda/next", h.HandleNextSegment)
+}
+
+// HandleSaveAgenda creates or replaces the agenda of an event.
+// Segments are given one per line as "Name, minutes". Every segment gets a timer,
+// and timers that haven't been started yet are reset to the segment duration.
+func (h *AgendaHandler) HandleSaveAgenda(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, err := strconv.ParseUint(c.FormValue("event"), 10, 64)
+	if err != nil || eventID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	segments, err := parseAgendaSegments(c.FormValue("segments"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid agenda: "+err.Error())
+	}
+
+	agenda := domain.Agenda{
+		EventID:     uint(eventID),
+		Segments:    segments,
+		AutoAdvance: c.FormValue("auto_advance") == "on",
+	}
+
+	// Keep the current segment when an existing agenda is edited
+	existing, err := h.agendaRepo.GetAgenda(ctx, uint(eventID))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get agenda: "+err.Error())
+	} else if err == nil && existing.CurrentIndex < len(segments) {
+		agenda.CurrentIndex = existing.CurrentIndex
+	}
+
+	timers := make([]domain.Timer, len(segments))
+	for i, segment := range segments {
+		timers[i], err = h.segmentTimer(ctx, agenda.EventID, segment)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to prepare segment timer: "+err.Error())
+		}
+	}
+
+	agenda, err = h.agendaRepo.SaveAgenda(ctx, agenda)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save agenda: "+err.Error())
+	}
+
+	return redirectToTimer(c, timers[agenda.CurrentIndex])
+}
+
+// HandleNextSegment moves the agenda of an event to its next segment and starts that segment's timer
+func (h *AgendaHandler) HandleNextSegment(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, err := strconv.ParseUint(c.FormValue("event"), 10, 64)
+	if err != nil || eventID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	from, err := strconv.Atoi(c.FormValue("from"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid segment")
+	}
+
+	timer, err := h.advance(ctx, uint(eventID), from)
+	if errors.Is(err, repository.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Agenda or segment timer not found")
+	} else if errors.Is(err, repository.ErrConflict) {
+		return echo.NewHTTPError(http.StatusConflict, "The agenda was moved on by someone else, please reload")
+	} else if errors.Is(err, domain.ErrNoNextSegment) {
+		return echo.NewHTTPError(http.StatusConflict, "This is already the last segment")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to advance agenda: "+err.Error())
+	}
+
+	return redirectToTimer(c, timer)
+}
+
+// AutoAdvance moves the agenda of a timer's event to its next segment when the timer of its
+// current segment ran out and the agenda has auto-advance turned on.
+// It is meant to be registered with TimerHandler.OnExpire.
+func (h *AgendaHandler) AutoAdvance(ctx context.Context, timer domain.Timer) {
+	if timer.EventID == 0 {
+		return
+	}
+
+	agenda, err := h.agendaRepo.GetAgenda(ctx, timer.EventID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return
+	} else if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to get agenda for event %d: %v\n", timer.EventID, err)
+		}
+		return
+	}
+
+	current, ok := agenda.CurrentSegment()
+	if !agenda.AutoAdvance || !ok || current.Name != timer.Segment || !agenda.HasNext() {
+		return
+	}
+
+	if _, err := h.advance(ctx, agenda.EventID, agenda.CurrentIndex); err != nil && !errors.Is(err, repository.ErrConflict) {
+		log.Printf("Failed to advance agenda of event %d: %v\n", agenda.EventID, err)
+	}
+}
+
+// advance moves the agenda of an event from segment from to the next one.
+// The timer of the segment it leaves is paused and the timer of the next segment
+// is started from its full duration. It returns the started timer.
+// The timers are updated before the agenda moves, so an agenda never moves on to a
+// segment whose timer couldn't be started.
+func (h *AgendaHandler) advance(ctx context.Context, eventID uint, from int) (domain.Timer, error) {
+	agenda, err := h.agendaRepo.GetAgenda(ctx, eventID)
+	if err != nil {
+		return domain.Timer{}, err
+	}
+	if agenda.CurrentIndex != from {
+		return domain.Timer{}, fmt.Errorf("agenda for event %d is at segment %d: %w", eventID, agenda.CurrentIndex, repository.ErrConflict)
+	}
+
+	current, _ := agenda.CurrentSegment()
+	if err := agenda.Advance(); err != nil {
+		return domain.Timer{}, err
+	}
+	next, _ := agenda.CurrentSegment()
+
+	now := time.Now()
+
+	// Stop the segment we leave so its overtime stays visible
+	previous, err := h.timerRepo.GetEventTimer(ctx, eventID, current.Name)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return domain.Timer{}, err
+	} else if err == nil && previous.IsRunning() {
+		if err := previous.Pause(now); err != nil {
+			return domain.Timer{}, err
+		}
+		if err := h.updateTimer(ctx, previous); err != nil {
+			return domain.Timer{}, err
+		}
+		previous.Version++
+		h.publish(ctx, domain.TimerEventPause, previous)
+	}
+
+	timer, err := h.segmentTimer(ctx, eventID, next)
+	if err != nil {
+		return domain.Timer{}, err
+	}
+	timer.Reset(next.Duration)
+	if err := timer.Start(now); err != nil {
+		return domain.Timer{}, err
+	}
+	if err := h.updateTimer(ctx, timer); err != nil {
+		return domain.Timer{}, err
+	}
+	timer.Version++
+
+	moved, err := h.agendaRepo.SetCurrentSegment(ctx, eventID, from, agenda.CurrentIndex)
+	if err != nil {
+		return domain.Timer{}, err
+	} else if !moved {
+		return domain.Timer{}, fmt.Errorf("agenda for event %d: %w", eventID, repository.ErrNotFound)
+	}
+
+	h.publish(ctx, domain.TimerEventAdvance, timer)
+	return timer, nil
+}
+
+// segmentTimer returns the timer of an agenda segment, creating it if it doesn't exist yet.
+// A timer that hasn't been started is reset to the segment duration.
+func (h *AgendaHandler) segmentTimer(ctx context.Context, eventID uint, segment domain.AgendaSegment) (domain.Timer, error) {
+	timer, err := h.timerRepo.GetEventTimer(ctx, eventID, segment.Name)
+	if errors.Is(err, repository.ErrNotFound) {
+		timer, err = h.timerRepo.CreateEventTimer(ctx, eventID, segment.Name, segment.Duration)
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			return timer, err
+		}
+		// Another request created the timer meanwhile
+		timer, err = h.timerRepo.GetEventTimer(ctx, eventID, segment.Name)
+	}
+	if err != nil {
+		return domain.Timer{}, err
+	}
+
+	if timer.State != domain.TimerStateIdle || timer.Duration == segment.Duration {
+		return timer, nil
+	}
+
+	timer.Reset(segment.Duration)
+	if err := h.updateTimer(ctx, timer); err != nil {
+		return domain.Timer{}, err
+	}
+	timer.Version++
+
+	h.publish(ctx, domain.TimerEventReset, timer)
+	return timer, nil
+}
+
+// updateTimer saves a segment timer, returning ErrNotFound if it was deleted since it was read
+func (h *AgendaHandler) updateTimer(ctx context.Context, timer domain.Timer) error {
+	updated, err := h.timerRepo.UpdateTimer(ctx, timer)
+	if err != nil {
+		return err
+	} else if !updated {
+		return fmt.Errorf("timer %d: %w", timer.ID, repository.ErrNotFound)
+	}
+	return nil
+}
+
+// publish records a timer state change in the timer log and broadcasts it to all connected clients
+func (h *AgendaHandler) publish(ctx context.Context, eventType domain.TimerEventType, timer domain.Timer) {
+	publishTimerEvent(ctx, h.timerHub, h.timerLogRepo, eventType, timer)
+}
+
+// parseAgendaSegments reads agenda segments given one per line as "Name, minutes"
+func parseAgendaSegments(text string) ([]domain.AgendaSegment, error) {
+	segments := make([]domain.AgendaSegment, 0)
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		comma := strings.LastIndex(line, ",")
+		if comma < 0 {
+			return nil, fmt.Errorf("segment %q must be written as \"Name, minutes\"", line)
+		}
+
+		name := strings.TrimSpace(line[:comma])
+		minutes, err := strconv.Atoi(strings.TrimSpace(line[comma+1:]))
+		if name == "" || err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("segment %q must have a name and a positive whole number of minutes", line)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("segment %q appears more than once", name)
+		}
+		seen[name] = true
+
+		segments = append(segments, domain.AgendaSegment{
+			Name:     name,
+			Duration: time.Duration(minutes) * time.Minute,
+		})
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.New("an agenda needs at least one segment")
+	}
+
+	return segments, nil
+}
+
+// redirectToTimer sends the client to the page of a timer
+func redirectToTimer(c echo.Context, timer domain.Timer) error {
+	timerURL := fmt.Sprintf("/timer?timer=%d", timer.ID)
+	if c.Request().Header.Get("HX-Request") == "true" {
+		c.Response().Header().Set("HX-Redirect", timerURL)
+		return c.NoContent(http.StatusOK)
+	}
+
+	return c.Redirect(http.StatusSeeOther, timerURL)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+// conflictingTimerRepository fails every update of the timer of one segment,
+// as if someone else changed that timer first
+type conflictingTimerRepository struct {
+	*mock.MockTimerRepository
+	segment string
+}
+
+func (r *conflictingTimerRepository) UpdateTimer(ctx context.Context, timer domain.Timer) (bool, error) {
+	if timer.Segment == r.segment {
+		return false, fmt.Errorf("timer %d: %w", timer.ID, repository.ErrConflict)
+	}
+	return r.MockTimerRepository.UpdateTimer(ctx, timer)
+}
+
+// deletedTimerRepository updates nothing for the timer of one segment, as if it was deleted after it was read
+type deletedTimerRepository struct {
+	*mock.MockTimerRepository
+	segment string
+}
+
+func (r *deletedTimerRepository) UpdateTimer(ctx context.Context, timer domain.Timer) (bool, error) {
+	if timer.Segment == r.segment {
+		return false, nil
+	}
+	return r.MockTimerRepository.UpdateTimer(ctx, timer)
+}
+
+// nextSegment asks to move the agenda of event 1, with the segments "talk" and "Q&A", from "talk"
+// to "Q&A" and returns the response and the index of the current segment afterwards
+func nextSegment(t *testing.T, timerRepo repository.TimerRepository) (*httptest.ResponseRecorder, int) {
+	t.Helper()
+
+	ctx := context.Background()
+	agendaRepo := mock.NewMockAgendaRepository()
+	h := NewAgendaHandler(agendaRepo, timerRepo, mock.NewMockTimerLogRepository(), pubsub.NewHub[domain.TimerEvent]())
+	e := echo.New()
+	h.RegisterRoutes(e)
+
+	_, err := agendaRepo.SaveAgenda(ctx, domain.Agenda{
+		EventID: 1,
+		Segments: []domain.AgendaSegment{
+			{Name: "talk", Duration: 20 * time.Minute},
+			{Name: "Q&A", Duration: 10 * time.Minute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveAgenda: %v", err)
+	}
+	if _, err := timerRepo.CreateEventTimer(ctx, 1, "Q&A", 10*time.Minute); err != nil {
+		t.Fatalf("CreateEventTimer: %v", err)
+	}
+
+	form := url.Values{"event": {"1"}, "from": {"0"}}
+	req := httptest.NewRequest(http.MethodPost, "/agenda/next", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	agenda, err := agendaRepo.GetAgenda(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAgenda: %v", err)
+	}
+	return rec, agenda.CurrentIndex
+}
+
+func TestNextSegmentKeepsAgendaWhenTimerConflicts(t *testing.T) {
+	rec, current := nextSegment(t, &conflictingTimerRepository{MockTimerRepository: mock.NewMockTimerRepository(), segment: "Q&A"})
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status: got %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if current != 0 {
+		t.Fatalf("current segment after the next timer conflicted: got %d, want 0", current)
+	}
+}
+
+func TestNextSegmentKeepsAgendaWhenTimerIsDeleted(t *testing.T) {
+	rec, current := nextSegment(t, &deletedTimerRepository{MockTimerRepository: mock.NewMockTimerRepository(), segment: "Q&A"})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status: got %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if current != 0 {
+		t.Fatalf("current segment after the next timer was deleted: got %d, want 0", current)
+	}
+}
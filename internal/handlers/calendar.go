@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// calendarName is the name calendar apps show for the feed
+const calendarName = "AI in Action"
+
+// CalendarHandler serves events as iCalendar files to subscribe to or import
+type CalendarHandler struct {
+	eventRepo repository.EventRepository
+	// clock stamps the calendars with the time they are generated
+	clock clock.Clock
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(eventRepo repository.EventRepository, clock clock.Clock) *CalendarHandler {
+	return &CalendarHandler{
+		eventRepo: eventRepo,
+		clock:     clock,
+	}
+}
+
+// RegisterRoutes registers the calendar routes
+func (h *CalendarHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/calendar.ics", h.HandleCalendarFeed)
+	e.GET("/events/:id/calendar.ics", h.HandleEventCalendar)
+}
+
+// HandleCalendarFeed returns the upcoming events as a calendar feed to subscribe to.
+// Open slots nobody claimed yet are left out.
+func (h *CalendarHandler) HandleCalendarFeed(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	upcoming, err := h.eventRepo.GetUpcomingEvents(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get upcoming events: "+err.Error())
+	}
+
+	events := make([]domain.Event, 0, len(upcoming))
+	for _, event := range upcoming {
+		if !event.Open {
+			events = append(events, event)
+		}
+	}
+
+	return h.writeCalendar(c, events)
+}
+
+// HandleEventCalendar returns a single event as a calendar file to download and add to a calendar
+func (h *CalendarHandler) HandleEventCalendar(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	event, err := loadEvent(ctx, c, h.eventRepo)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"event-%d.ics\"", event.ID))
+	return h.writeCalendar(c, []domain.Event{event})
+}
+
+// writeCalendar responds with events as an iCalendar file
+func (h *CalendarHandler) writeCalendar(c echo.Context, events []domain.Event) error {
+	calendar := ical.Calendar{
+		Name:   calendarName,
+		Events: make([]ical.Event, len(events)),
+	}
+	for i, event := range events {
+		calendar.Events[i] = calendarEvent(c, event)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return ical.Encode(c.Response().Writer, calendar, h.clock.Now())
+}
+
+// calendarEvent converts an event to a calendar event linking to the event's page.
+// Open slots are tentative, as nobody has claimed them yet.
+func calendarEvent(c echo.Context, event domain.Event) ical.Event {
+	calendarEvent := ical.Event{
+		UID:         event.CalendarUID(),
+		Sequence:    event.Sequence,
+		Modified:    event.UpdatedAt,
+		Start:       event.Date,
+		End:         event.End(),
+		Summary:     event.Title,
+		Description: fmt.Sprintf("Speaker: %s\n\n%s", event.Speaker, event.Description),
+		URL:         fmt.Sprintf("%s://%s/events/%d", c.Scheme(), c.Request().Host, event.ID),
+		Status:      "CONFIRMED",
+	}
+	if event.Open {
+		calendarEvent.Description = "Open slot, nobody has claimed this session yet."
+		calendarEvent.Status = "TENTATIVE"
+	}
+	return calendarEvent
+}
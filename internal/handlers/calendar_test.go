@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+// newCalendarServer returns a server with the calendar routes over the mock events and an open slot
+func newCalendarServer(t *testing.T) (*echo.Echo, domain.Event) {
+	t.Helper()
+	now := time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC)
+	eventRepo := mock.NewMockEventRepository(clock.Fixed(now), time.UTC)
+
+	slot := domain.Event{
+		Title:      "Weekly AI in Action",
+		Date:       now.AddDate(0, 0, 3),
+		TimeZone:   "UTC",
+		SeriesID:   1,
+		Occurrence: now.AddDate(0, 0, 3),
+		Open:       true,
+	}
+	if _, err := eventRepo.AddSeriesSlots(context.Background(), []domain.Event{slot}); err != nil {
+		t.Fatalf("AddSeriesSlots: %v", err)
+	}
+	upcoming, err := eventRepo.GetUpcomingEvents(context.Background())
+	if err != nil {
+		t.Fatalf("GetUpcomingEvents: %v", err)
+	}
+	for _, event := range upcoming {
+		if event.Open {
+			slot = event
+		}
+	}
+
+	e := echo.New()
+	NewCalendarHandler(eventRepo, clock.Fixed(now)).RegisterRoutes(e)
+	return e, slot
+}
+
+// getCalendar requests a calendar file and decodes it
+func getCalendar(t *testing.T, e *echo.Echo, target string) ical.Calendar {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: got status %d, want %d", target, rec.Code, http.StatusOK)
+	}
+
+	calendar, err := ical.Decode(rec.Body, time.UTC)
+	if err != nil {
+		t.Fatalf("%s: decode: %v", target, err)
+	}
+	return calendar
+}
+
+func TestCalendarFeedLeavesOutOpenSlots(t *testing.T) {
+	e, slot := newCalendarServer(t)
+
+	calendar := getCalendar(t, e, "/calendar.ics")
+	if len(calendar.Events) == 0 {
+		t.Fatal("feed has no events")
+	}
+	for _, event := range calendar.Events {
+		if event.Summary == slot.Title {
+			t.Fatalf("feed lists the open slot %q", slot.Title)
+		}
+		if !strings.HasPrefix(event.URL, "http://example.com/events/") {
+			t.Fatalf("event %q links to %q, want its event page", event.Summary, event.URL)
+		}
+		if event.Status != "CONFIRMED" {
+			t.Fatalf("event %q: got status %q, want CONFIRMED", event.Summary, event.Status)
+		}
+	}
+}
+
+func TestEventCalendarMarksOpenSlotsTentative(t *testing.T) {
+	e, slot := newCalendarServer(t)
+
+	calendar := getCalendar(t, e, fmt.Sprintf("/events/%d/calendar.ics", slot.ID))
+	if len(calendar.Events) != 1 {
+		t.Fatalf("got %d events, want 1", len(calendar.Events))
+	}
+	if got := calendar.Events[0].Status; got != "TENTATIVE" {
+		t.Fatalf("got status %q, want TENTATIVE", got)
+	}
+}
@@ -2,24 +2,35 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
 	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 )
 
+// pastEventsPageSize is the number of past events shown at once in the archive
+const pastEventsPageSize = 10
+
 // EventHandler handles event-related requests
 type EventHandler struct {
 	eventRepo repository.EventRepository
+	// timeZone is the group's timezone, preselected in the form of new events
+	timeZone *time.Location
 }
 
 // NewEventHandler creates a new event handler
-func NewEventHandler(eventRepo repository.EventRepository) *EventHandler {
+func NewEventHandler(eventRepo repository.EventRepository, timeZone *time.Location) *EventHandler {
 	return &EventHandler{
 		eventRepo: eventRepo,
+		timeZone:  timeZone,
 	}
 }
 
@@ -27,7 +38,13 @@ func NewEventHandler(eventRepo repository.EventRepository) *EventHandler {
 func (h *EventHandler) RegisterRoutes(e *echo.Echo) {
 	e.GET("/", h.HandleTimelinePage)
 	e.GET("/events/add-form", h.HandleAddEventForm)
+	e.GET("/events/past", h.HandlePastEvents)
 	e.POST("/events/add", h.HandleAddEvent)
+	e.GET("/events/:id/edit-form", h.HandleEditEventForm)
+	e.POST("/events/:id/edit", h.HandleEditEvent)
+	e.POST("/events/:id/delete", h.HandleDeleteEvent)
+	e.GET("/events/:id/claim-form", h.HandleClaimSlotForm)
+	e.POST("/events/:id/claim", h.HandleClaimSlot)
 }
 
 // HandleTimelinePage renders the timeline page with upcoming and past events
@@ -41,20 +58,47 @@ func (h *EventHandler) HandleTimelinePage(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get upcoming events: "+err.Error())
 	}
 
-	// Get past events
-	pastEvents, err := h.eventRepo.GetPastEvents(ctx)
+	// Get the first page of past events
+	pastEvents, err := h.pastEventsPage(ctx, "", "")
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get past events: "+err.Error())
+		return err
 	}
 
 	// Render the timeline page
 	return pages.Timeline(upcomingEvents, pastEvents).Render(ctx, c.Response().Writer)
 }
 
+// HandlePastEvents renders a page of the past events archive matching the "q" search,
+// starting at the "cursor" parameter or at the newest event without one
+func (h *EventHandler) HandlePastEvents(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	page, err := h.pastEventsPage(ctx, c.QueryParam("q"), c.QueryParam("cursor"))
+	if err != nil {
+		return err
+	}
+
+	return components.PastEventItems(page).Render(ctx, c.Response().Writer)
+}
+
 // HandleAddEventForm renders the form for adding a new event
 func (h *EventHandler) HandleAddEventForm(c echo.Context) error {
 	ctx := c.Request().Context()
-	return pages.AddEventForm().Render(ctx, c.Response().Writer)
+	return pages.AddEventForm(domain.Event{TimeZone: h.timeZone.String()}).Render(ctx, c.Response().Writer)
+}
+
+// HandleEditEventForm renders the event form prefilled with an existing event
+func (h *EventHandler) HandleEditEventForm(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	event, err := loadEvent(ctx, c, h.eventRepo)
+	if err != nil {
+		return err
+	}
+
+	return pages.AddEventForm(event).Render(ctx, c.Response().Writer)
 }
 
 // HandleAddEvent handles the submission of a new event
@@ -62,49 +106,129 @@ func (h *EventHandler) HandleAddEvent(c echo.Context) error {
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
 	defer cancel()
 
-	// Parse form data
-	title := c.FormValue("title")
-	speaker := c.FormValue("speaker")
-	description := c.FormValue("description")
-	dateStr := c.FormValue("date")
-	timeStr := c.FormValue("time")
+	event, err := parseEventForm(c)
+	if err != nil {
+		return err
+	}
 
-	// Validate required fields
-	if title == "" || speaker == "" || description == "" || dateStr == "" || timeStr == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "All fields are required")
+	// Add event to repository
+	_, err = h.eventRepo.AddEvent(ctx, event)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add event: "+err.Error())
 	}
 
-	// Parse date and time
-	dateTimeStr := dateStr + "T" + timeStr + ":00"
-	eventDate, err := time.Parse("2006-01-02T15:04:05", dateTimeStr)
+	return h.renderTimeline(ctx, c)
+}
+
+// HandleEditEvent handles the submission of the edit form of an event
+func (h *EventHandler) HandleEditEvent(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	existing, err := loadEvent(ctx, c, h.eventRepo)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid date or time format")
+		return err
 	}
 
-	// Create new event
-	event := domain.Event{
-		Title:       title,
-		Speaker:     speaker,
-		Description: description,
-		Date:        eventDate,
-		IsUpcoming:  eventDate.After(time.Now()),
+	event, err := parseEventForm(c)
+	if err != nil {
+		return err
 	}
+	event.ID = existing.ID
 
-	// Add event to repository
-	_, err = h.eventRepo.AddEvent(ctx, event)
+	updated, err := h.eventRepo.UpdateEvent(ctx, event)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add event: "+err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update event: "+err.Error())
+	} else if !updated {
+		return echo.NewHTTPError(http.StatusNotFound, "Event not found")
+	}
+
+	return h.renderTimeline(ctx, c)
+}
+
+// HandleDeleteEvent removes an event from the timeline.
+// The page asks for confirmation before sending the request.
+func (h *EventHandler) HandleDeleteEvent(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
 	}
 
+	deleted, err := h.eventRepo.DeleteEvent(ctx, uint(id))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete event: "+err.Error())
+	} else if !deleted {
+		return echo.NewHTTPError(http.StatusNotFound, "Event not found")
+	}
+
+	return h.renderTimeline(ctx, c)
+}
+
+// HandleClaimSlotForm renders the form for claiming an open slot
+func (h *EventHandler) HandleClaimSlotForm(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	event, err := loadEvent(ctx, c, h.eventRepo)
+	if err != nil {
+		return err
+	}
+	if !event.Open {
+		return echo.NewHTTPError(http.StatusConflict, "This slot was claimed already")
+	}
+
+	return pages.ClaimSlotForm(event).Render(ctx, c.Response().Writer)
+}
+
+// HandleClaimSlot turns an open slot into a talk. When two speakers claim the same slot,
+// the first one gets it and the other one is told in the claim form.
+func (h *EventHandler) HandleClaimSlot(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	speakers := parseSpeakerFields(c)
+	event := domain.Event{
+		ID:          uint(id),
+		Title:       c.FormValue("title"),
+		Speaker:     domain.SpeakerLine(speakers),
+		Speakers:    speakers,
+		Description: c.FormValue("description"),
+	}
+	if event.Title == "" || len(speakers) == 0 || event.Description == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "All fields are required")
+	}
+
+	claimed, err := h.eventRepo.ClaimSlot(ctx, event)
+	if errors.Is(err, repository.ErrConflict) {
+		return formErrorStatus(c, http.StatusConflict, "#claim-form-error", "Someone else claimed this slot first. Pick another open slot.")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to claim slot: "+err.Error())
+	} else if !claimed {
+		return echo.NewHTTPError(http.StatusNotFound, "Event not found")
+	}
+
+	return h.renderTimeline(ctx, c)
+}
+
+// renderTimeline renders the timeline content for HTMX requests and the full timeline page otherwise
+func (h *EventHandler) renderTimeline(ctx context.Context, c echo.Context) error {
 	// Get updated events for the response
 	upcomingEvents, err := h.eventRepo.GetUpcomingEvents(ctx)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get upcoming events: "+err.Error())
 	}
 
-	pastEvents, err := h.eventRepo.GetPastEvents(ctx)
+	pastEvents, err := h.pastEventsPage(ctx, "", "")
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get past events: "+err.Error())
+		return err
 	}
 
 	// Check if this is an HTMX request
@@ -116,3 +240,134 @@ func (h *EventHandler) HandleAddEvent(c echo.Context) error {
 	// Return the full timeline page for regular requests
 	return pages.Timeline(upcomingEvents, pastEvents).Render(ctx, c.Response().Writer)
 }
+
+// pastEventsPage loads the page of past events matching search that starts at cursor
+func (h *EventHandler) pastEventsPage(ctx context.Context, search string, cursor string) (components.PastEventsPage, error) {
+	var after *repository.EventCursor
+	if cursor != "" {
+		parsed, err := parseEventCursor(cursor)
+		if err != nil {
+			return components.PastEventsPage{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+		after = &parsed
+	}
+
+	// One more event than shown tells whether there is a next page
+	events, err := h.eventRepo.SearchPastEvents(ctx, search, after, pastEventsPageSize+1)
+	if err != nil {
+		return components.PastEventsPage{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get past events: "+err.Error())
+	}
+
+	page := components.PastEventsPage{
+		Events: events,
+		Search: search,
+		Cursor: cursor,
+	}
+	if len(events) > pastEventsPageSize {
+		page.Events = events[:pastEventsPageSize]
+		page.NextCursor = formatEventCursor(page.Events[pastEventsPageSize-1])
+	}
+
+	return page, nil
+}
+
+// formatEventCursor returns the cursor of the page starting after event, as "<date in unix nanoseconds>_<id>"
+func formatEventCursor(event domain.Event) string {
+	return fmt.Sprintf("%d_%d", event.Date.UnixNano(), event.ID)
+}
+
+// parseEventCursor parses a cursor made by formatEventCursor
+func parseEventCursor(cursor string) (repository.EventCursor, error) {
+	date, id, found := strings.Cut(cursor, "_")
+	if !found {
+		return repository.EventCursor{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	nanos, err := strconv.ParseInt(date, 10, 64)
+	if err != nil {
+		return repository.EventCursor{}, fmt.Errorf("invalid cursor date: %w", err)
+	}
+
+	eventID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return repository.EventCursor{}, fmt.Errorf("invalid cursor ID: %w", err)
+	}
+
+	return repository.EventCursor{Date: time.Unix(0, nanos), ID: uint(eventID)}, nil
+}
+
+// loadEvent returns the event given by the "id" path parameter
+func loadEvent(ctx context.Context, c echo.Context, eventRepo repository.EventRepository) (domain.Event, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return domain.Event{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	event, err := eventRepo.GetEvent(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return domain.Event{}, echo.NewHTTPError(http.StatusNotFound, "Event not found")
+	} else if err != nil {
+		return domain.Event{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get event: "+err.Error())
+	}
+
+	return event, nil
+}
+
+// parseEventForm reads an event from the fields of the event form
+func parseEventForm(c echo.Context) (domain.Event, error) {
+	// Parse form data
+	title := c.FormValue("title")
+	speakers := parseSpeakerFields(c)
+	description := c.FormValue("description")
+	dateStr := c.FormValue("date")
+	timeStr := c.FormValue("time")
+	timeZone := c.FormValue("time_zone")
+	durationStr := c.FormValue("duration")
+
+	// Validate required fields
+	if title == "" || len(speakers) == 0 || description == "" || dateStr == "" || timeStr == "" || timeZone == "" {
+		return domain.Event{}, echo.NewHTTPError(http.StatusBadRequest, "All fields are required")
+	}
+
+	location, err := domain.LoadTimeZone(timeZone)
+	if err != nil {
+		return domain.Event{}, echo.NewHTTPError(http.StatusBadRequest, "Unknown timezone "+timeZone)
+	}
+
+	// Parse date and time as the wall clock time in the event's timezone
+	dateTimeStr := dateStr + "T" + timeStr + ":00"
+	eventDate, err := time.ParseInLocation("2006-01-02T15:04:05", dateTimeStr, location)
+	if err != nil {
+		return domain.Event{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid date or time format")
+	}
+
+	// Without a duration the event takes the default EventLength
+	var duration time.Duration
+	if durationStr != "" {
+		minutes, err := strconv.Atoi(durationStr)
+		if err != nil || minutes < 1 || minutes > 24*60 {
+			return domain.Event{}, echo.NewHTTPError(http.StatusBadRequest, "Duration must be between 1 minute and 24 hours")
+		}
+		duration = time.Duration(minutes) * time.Minute
+	}
+
+	return domain.Event{
+		Title:       title,
+		Speaker:     domain.SpeakerLine(speakers),
+		Speakers:    speakers,
+		Description: description,
+		Date:        eventDate,
+		TimeZone:    location.String(),
+		Duration:    duration,
+	}, nil
+}
+
+// parseSpeakerFields reads the speakers of the "speaker" fields of the event and claim forms.
+// Each field holds a speaker, or several separated like in a speaker line.
+func parseSpeakerFields(c echo.Context) []domain.Speaker {
+	form, err := c.FormParams()
+	if err != nil {
+		return nil
+	}
+	return domain.ParseSpeakers(strings.Join(form["speaker"], ","))
+}
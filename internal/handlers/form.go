@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/labstack/echo/v4"
+)
+
+// formError responds to a form that can't be saved. HTMX requests get the message to show
+// in the element matching the target selector, with a status the page script swaps in.
+func formError(c echo.Context, target string, message string) error {
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return echo.NewHTTPError(http.StatusBadRequest, message)
+	}
+
+	c.Response().Header().Set("HX-Retarget", target)
+	c.Response().WriteHeader(http.StatusUnprocessableEntity)
+	return components.FormError(message).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// formErrorStatus responds to a form that was rejected with status, like formError does,
+// for errors that aren't about what was filled in
+func formErrorStatus(c echo.Context, status int, target string, message string) error {
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return echo.NewHTTPError(status, message)
+	}
+
+	c.Response().Header().Set("HX-Retarget", target)
+	c.Response().WriteHeader(status)
+	return components.FormError(message).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -1,17 +1,83 @@
 package handlers
 
 import (
+	"context"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
 	"github.com/labstack/echo/v4"
 )
 
-// RegisterHandlers registers all handlers with the Echo instance
-func RegisterHandlers(e *echo.Echo, eventRepo repository.EventRepository, timerRepo repository.TimerRepository, noteRepo repository.NoteRepository, questionRepo repository.QuestionRepository) {
+// Config holds the handler settings given on the command line
+type Config struct {
+	// HostKey protects the host's moderation pages; without one they are closed to everyone
+	HostKey string
+	// QuestionsPerMinute is how many questions one client can submit per minute, 0 turns the limit off
+	QuestionsPerMinute float64
+	// QuestionBurst is how many questions one client can submit in a row before the limit applies
+	QuestionBurst int
+	// Clock decides which event is running now
+	Clock clock.Clock
+	// TimeZone is the group's timezone, which new events are scheduled in unless another one is picked
+	TimeZone *time.Location
+}
+
+// RegisterHandlers registers all handlers with the Echo instance.
+// Background workers started here (like the timer ticker) run until ctx is cancelled.
+func RegisterHandlers(ctx context.Context, e *echo.Echo, repos repository.Repositories, config Config) {
 	// Register event handlers
-	eventHandler := NewEventHandler(eventRepo)
+	eventHandler := NewEventHandler(repos.Events, config.TimeZone)
 	eventHandler.RegisterRoutes(e)
 
-	// TODO: Register timer handlers
-	// TODO: Register note handlers
-	// TODO: Register question handlers
+	// Register calendar handlers
+	calendarHandler := NewCalendarHandler(repos.Events, config.Clock)
+	calendarHandler.RegisterRoutes(e)
+
+	// Register import handlers
+	importHandler := NewImportHandler(repos.Events, config.TimeZone)
+	importHandler.RegisterRoutes(e)
+
+	// Register speaker handlers
+	speakerHandler := NewSpeakerHandler(repos.Speakers, repos.Events, config.Clock)
+	speakerHandler.RegisterRoutes(e)
+
+	// Register resource handlers
+	resourceHandler := NewResourceHandler(repos.Resources, repos.Events)
+	resourceHandler.RegisterRoutes(e)
+
+	// Register series handlers
+	seriesHandler := NewSeriesHandler(repos.Series, repos.Events, config.Clock, config.TimeZone)
+	seriesHandler.RegisterRoutes(e)
+	go seriesHandler.RunSlotGenerator(ctx)
+
+	// Register timer handlers
+	timerHub := pubsub.NewHub[domain.TimerEvent]()
+	timerHandler := NewTimerHandler(repos.Timers, repos.Agendas, repos.TimerLogs, timerHub)
+	timerHandler.RegisterRoutes(e)
+	go timerHandler.RunTicker(ctx)
+
+	// Register agenda handlers
+	agendaHandler := NewAgendaHandler(repos.Agendas, repos.Timers, repos.TimerLogs, timerHub)
+	agendaHandler.RegisterRoutes(e)
+	timerHandler.OnExpire(agendaHandler.AutoAdvance)
+
+	// Register report handlers
+	reportHandler := NewReportHandler(repos.Events, repos.TimerLogs)
+	reportHandler.RegisterRoutes(e)
+
+	// Register note handlers
+	noteHandler := NewNoteHandler(repos.Notes, repos.Events)
+	noteHandler.RegisterRoutes(e)
+
+	// Register Markdown handlers
+	markdownHandler := NewMarkdownHandler()
+	markdownHandler.RegisterRoutes(e)
+
+	// Register question handlers
+	questionHub := pubsub.NewHub[domain.QuestionEvent]()
+	questionHandler := NewQuestionHandler(repos.Questions, repos.Events, questionHub, config.HostKey, questionRateLimiter(config.QuestionsPerMinute, config.QuestionBurst), config.Clock)
+	questionHandler.RegisterRoutes(e)
 }
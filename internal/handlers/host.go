@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// hostCookieName is the cookie that marks a browser as the host's
+const hostCookieName = "aia_host"
+
+// requireHost returns middleware that only lets the host through.
+// The host opens any host page once with "?key=<host key>", which remembers the browser in a cookie
+// holding a token derived from the key, not the key itself. Without a host key nobody gets through.
+func requireHost(hostKey string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if hostKey == "" {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the host can moderate questions")
+			}
+
+			if cookie, err := c.Cookie(hostCookieName); err == nil && sameKey(cookie.Value, hostToken(hostKey)) {
+				return next(c)
+			}
+
+			if !sameKey(c.QueryParam("key"), hostKey) {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the host can moderate questions")
+			}
+
+			c.SetCookie(&http.Cookie{
+				Name:     hostCookieName,
+				Value:    hostToken(hostKey),
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   c.Scheme() == "https",
+				SameSite: http.SameSiteLaxMode,
+			})
+			return next(c)
+		}
+	}
+}
+
+// NewHostKey returns a random host key, for servers started without one
+func NewHostKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hostToken returns the token the host cookie holds for hostKey
+func hostToken(hostKey string) string {
+	mac := hmac.New(sha256.New, []byte(hostKey))
+	mac.Write([]byte(hostCookieName))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sameKey compares a given key with the host key in constant time
+func sameKey(given string, hostKey string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(hostKey)) == 1
+}
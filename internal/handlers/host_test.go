@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newHostServer returns a server with a host page protected by hostKey
+func newHostServer(hostKey string) *echo.Echo {
+	e := echo.New()
+	e.GET("/host", func(c echo.Context) error {
+		return c.String(http.StatusOK, "moderation")
+	}, requireHost(hostKey))
+	return e
+}
+
+// getHost requests the host page at target with the given cookies
+func getHost(e *echo.Echo, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireHostWithoutKey(t *testing.T) {
+	e := newHostServer("")
+
+	if rec := getHost(e, "/host", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := getHost(e, "/host?key=", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("empty key: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireHostWithKey(t *testing.T) {
+	e := newHostServer("secret")
+
+	if rec := getHost(e, "/host", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("no key: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := getHost(e, "/host?key=wrong", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("wrong key: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec := getHost(e, "/host?key=secret", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("host key: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != hostCookieName {
+		t.Fatalf("got cookies %v, want the host cookie", cookies)
+	}
+	if strings.Contains(cookies[0].Value, "secret") {
+		t.Fatalf("host cookie %q holds the host key", cookies[0].Value)
+	}
+	if cookies[0].Secure {
+		t.Fatal("host cookie is Secure on a plain HTTP request")
+	}
+
+	// The cookie lets the host in without the key
+	if rec := getHost(e, "/host", cookies); rec.Code != http.StatusOK {
+		t.Fatalf("host cookie: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// The cookie of another key doesn't
+	other := []*http.Cookie{{Name: hostCookieName, Value: hostToken("other")}}
+	if rec := getHost(e, "/host", other); rec.Code != http.StatusForbidden {
+		t.Fatalf("cookie of another key: got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireHostSecureCookie(t *testing.T) {
+	e := newHostServer("secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/host?key=secret", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].Secure {
+		t.Fatalf("got cookies %v, want a Secure host cookie over HTTPS", cookies)
+	}
+}
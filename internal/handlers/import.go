@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/eventimport"
+	"github.com/go-go-golems/ai-in-action-app/internal/ical"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// maxCalendarSize is the largest calendar file that can be uploaded
+const maxCalendarSize = 5 << 20
+
+// ImportHandler handles importing events from uploaded iCalendar files
+type ImportHandler struct {
+	importer *eventimport.Importer
+	// timeZone is the group's timezone, which times without a timezone are read in
+	timeZone *time.Location
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(eventRepo repository.EventRepository, timeZone *time.Location) *ImportHandler {
+	return &ImportHandler{
+		importer: eventimport.NewImporter(eventRepo, timeZone),
+		timeZone: timeZone,
+	}
+}
+
+// RegisterRoutes registers the import routes
+func (h *ImportHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/events/import", h.HandleImportPage)
+	e.POST("/events/import", h.HandleImport)
+}
+
+// HandleImportPage renders the page to upload a calendar file
+func (h *ImportHandler) HandleImportPage(c echo.Context) error {
+	return pages.ImportEvents(nil).Render(c.Request().Context(), c.Response().Writer)
+}
+
+// HandleImport imports the events of an uploaded calendar file, or only shows what
+// would change when the "dry_run" field is set
+func (h *ImportHandler) HandleImport(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+
+	dryRun := c.FormValue("dry_run") == "true"
+
+	file, err := c.FormFile("calendar")
+	if err != nil {
+		return formError(c, "#import-result", "Choose a calendar file to import")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open upload: "+err.Error())
+	}
+	defer src.Close()
+
+	// Read one byte more than allowed to tell a file of the maximum size from a larger one
+	data, err := io.ReadAll(io.LimitReader(src, maxCalendarSize+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to read upload: "+err.Error())
+	}
+	if len(data) > maxCalendarSize {
+		return formErrorStatus(c, http.StatusRequestEntityTooLarge, "#import-result", "The file is too large, calendars can be at most 5 MB")
+	}
+
+	calendar, err := ical.Decode(bytes.NewReader(data), h.timeZone)
+	if err != nil {
+		return formError(c, "#import-result", "The file isn't a calendar that can be imported: "+err.Error())
+	}
+
+	changes, err := h.importer.Plan(ctx, calendar)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to plan import: "+err.Error())
+	}
+
+	if !dryRun {
+		err := h.importer.Apply(ctx, changes)
+		if errors.Is(err, repository.ErrNotFound) {
+			return formErrorStatus(c, http.StatusConflict, "#import-result", "An event of the calendar was deleted while importing, please import the file again")
+		} else if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import events: "+err.Error())
+		}
+	}
+
+	summary := components.ImportSummary{
+		Changes: changes,
+		DryRun:  dryRun,
+	}
+
+	// Check if this is an HTMX request
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.ImportResult(summary).Render(ctx, c.Response().Writer)
+	}
+
+	return pages.ImportEvents(&summary).Render(ctx, c.Response().Writer)
+}
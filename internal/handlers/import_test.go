@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+func TestImportRejectsLargeFiles(t *testing.T) {
+	now := clock.Fixed(time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC))
+	h := NewImportHandler(mock.NewMockEventRepository(now, time.UTC), time.UTC)
+	e := echo.New()
+	h.RegisterRoutes(e)
+
+	// A valid calendar padded past the limit, so cutting it off would still parse
+	calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nEND:VCALENDAR\r\n"
+	padding := strings.Repeat("X-PADDING:"+strings.Repeat("x", 62)+"\r\n", maxCalendarSize/74+1)
+	content := strings.Replace(calendar, "END:VCALENDAR", padding+"END:VCALENDAR", 1)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("calendar", "big.ics")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	if _, err := part.Write([]byte(content)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := form.WriteField("dry_run", "true"); err != nil {
+		t.Fatalf("WriteField: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/events/import", &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status: got %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if !strings.Contains(rec.Body.String(), "too large") {
+		t.Fatalf("body doesn't say the file is too large: %s", rec.Body.String())
+	}
+	if got := rec.Header().Get("HX-Retarget"); got != "#import-result" {
+		t.Fatalf("HX-Retarget: got %q, want %q", got, "#import-result")
+	}
+}
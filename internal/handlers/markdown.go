@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/labstack/echo/v4"
+)
+
+// MarkdownHandler handles the live Markdown preview of editors
+type MarkdownHandler struct{}
+
+// NewMarkdownHandler creates a new Markdown handler
+func NewMarkdownHandler() *MarkdownHandler {
+	return &MarkdownHandler{}
+}
+
+// RegisterRoutes registers the Markdown routes
+func (h *MarkdownHandler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/markdown/preview", h.HandlePreview)
+}
+
+// HandlePreview renders the Markdown of a form field as sanitized HTML.
+// The "field" parameter names the field to render and defaults to "content".
+func (h *MarkdownHandler) HandlePreview(c echo.Context) error {
+	field := c.QueryParam("field")
+	if field == "" {
+		field = "content"
+	}
+
+	return components.MarkdownPreview(c.FormValue(field)).Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// HandleModerationPage renders the host's moderation view of the question queue,
+// including hidden and merged questions
+func (h *QuestionHandler) HandleModerationPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	queue, events, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return pages.Moderation(queue, events).Render(ctx, c.Response().Writer)
+}
+
+// HandleModerationList renders just the moderation lists
+func (h *QuestionHandler) HandleModerationList(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	queue, _, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return pages.ModerationContent(queue).Render(ctx, c.Response().Writer)
+}
+
+// HandleModerationEvents streams the moderation lists of an event to the host as Server-Sent Events,
+// so new questions and votes show up without a reload. Unlike the attendee stream it includes
+// hidden and merged questions, which is why it needs the host key.
+func (h *QuestionHandler) HandleModerationEvents(c echo.Context) error {
+	return h.streamQueue(c, pages.ModerationUpdate)
+}
+
+// HandleMarkAnswered marks a pending or pinned question as answered
+func (h *QuestionHandler) HandleMarkAnswered(c echo.Context) error {
+	return h.moderate(c, domain.QuestionEventAnswer, func(ctx context.Context, question *domain.Question) (bool, error) {
+		if err := question.Answer(); err != nil {
+			return false, err
+		}
+		return h.questionRepo.MarkAsAnswered(ctx, question.ID)
+	})
+}
+
+// HandlePin pins a pending question as the one being answered now.
+// The question pinned before it is marked answered.
+func (h *QuestionHandler) HandlePin(c echo.Context) error {
+	return h.moderate(c, domain.QuestionEventPin, func(ctx context.Context, question *domain.Question) (bool, error) {
+		if err := question.Pin(); err != nil {
+			return false, err
+		}
+		return h.questionRepo.PinQuestion(ctx, question.ID)
+	})
+}
+
+// HandleHide hides a question from attendees
+func (h *QuestionHandler) HandleHide(c echo.Context) error {
+	return h.moderate(c, domain.QuestionEventHide, func(ctx context.Context, question *domain.Question) (bool, error) {
+		from := question.State
+		if err := question.Hide(); err != nil {
+			return false, err
+		}
+		return h.questionRepo.SetQuestionState(ctx, question.ID, from, question.State)
+	})
+}
+
+// HandleRestore puts a hidden question back into the queue
+func (h *QuestionHandler) HandleRestore(c echo.Context) error {
+	return h.moderate(c, domain.QuestionEventRestore, func(ctx context.Context, question *domain.Question) (bool, error) {
+		from := question.State
+		if err := question.Restore(); err != nil {
+			return false, err
+		}
+		return h.questionRepo.SetQuestionState(ctx, question.ID, from, question.State)
+	})
+}
+
+// HandleMerge folds a duplicate question into the question given by the "target" parameter
+func (h *QuestionHandler) HandleMerge(c echo.Context) error {
+	targetID, err := strconv.ParseUint(c.FormValue("target"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Choose a question to merge into")
+	}
+
+	return h.moderate(c, domain.QuestionEventMerge, func(ctx context.Context, question *domain.Question) (bool, error) {
+		target, err := h.questionRepo.GetQuestion(ctx, uint(targetID))
+		if err != nil {
+			return false, err
+		}
+		if err := question.MergeInto(target); err != nil {
+			return false, err
+		}
+		return h.questionRepo.MergeQuestions(ctx, question.ID, target.ID)
+	})
+}
+
+// HandleEditQuestion renders the editor for a question in place of its moderation card
+func (h *QuestionHandler) HandleEditQuestion(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	question, err := h.loadQuestion(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, moderationURL(question.EventID))
+	}
+
+	return components.QuestionEditor(question).Render(ctx, c.Response().Writer)
+}
+
+// HandleSaveQuestion saves the edited name and content of a question.
+// Clearing the name makes the question anonymous.
+func (h *QuestionHandler) HandleSaveQuestion(c echo.Context) error {
+	name := strings.TrimSpace(c.FormValue("name"))
+	content := strings.TrimSpace(c.FormValue("content"))
+	if content == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Question is required")
+	}
+	if len(content) > maxQuestionLength {
+		return echo.NewHTTPError(http.StatusBadRequest, "Questions can be at most "+strconv.Itoa(maxQuestionLength)+" characters long")
+	}
+
+	return h.moderate(c, domain.QuestionEventEdit, func(ctx context.Context, question *domain.Question) (bool, error) {
+		if err := question.Edit(name, content); err != nil {
+			return false, err
+		}
+		return h.questionRepo.UpdateQuestion(ctx, *question)
+	})
+}
+
+// moderate applies a moderation action to the question given by the "id" path parameter,
+// publishes the change and renders the moderation lists of its event
+func (h *QuestionHandler) moderate(c echo.Context, action domain.QuestionEventType, apply func(ctx context.Context, question *domain.Question) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	question, err := h.loadQuestion(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := apply(ctx, &question)
+	if errors.Is(err, domain.ErrInvalidQuestionTransition) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	} else if errors.Is(err, repository.ErrConflict) {
+		return echo.NewHTTPError(http.StatusConflict, "The question was moderated by someone else meanwhile, please reload")
+	} else if errors.Is(err, repository.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Question not found")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to "+string(action)+" question: "+err.Error())
+	} else if !updated {
+		return echo.NewHTTPError(http.StatusNotFound, "Question not found")
+	}
+	h.publish(action, question)
+
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, moderationURL(question.EventID))
+	}
+
+	queue, err := h.eventQueue(ctx, c, domain.Event{ID: question.EventID})
+	if err != nil {
+		return err
+	}
+
+	return pages.ModerationContent(queue).Render(ctx, c.Response().Writer)
+}
+
+// moderationURL returns the moderation page of an event
+func moderationURL(eventID uint) string {
+	return fmt.Sprintf("/host/questions?event=%d", eventID)
+}
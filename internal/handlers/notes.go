@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// NoteHandler handles speaker note requests.
+// Notes are shown next to the timer, so the handlers render the note panel
+// for HTMX requests and send other requests back to the timer page.
+// The "event" and optional "speaker" parameters select whose notes are shown.
+type NoteHandler struct {
+	noteRepo  repository.NoteRepository
+	eventRepo repository.EventRepository
+}
+
+// NewNoteHandler creates a new note handler
+func NewNoteHandler(noteRepo repository.NoteRepository, eventRepo repository.EventRepository) *NoteHandler {
+	return &NoteHandler{
+		noteRepo:  noteRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+// RegisterRoutes registers the note routes
+func (h *NoteHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/notes", h.HandleNote)
+	e.GET("/notes/next", h.HandleNextNote)
+	e.GET("/notes/prev", h.HandlePreviousNote)
+	e.GET("/notes/edit", h.HandleEditNote)
+	e.POST("/notes/save", h.HandleSaveNote)
+}
+
+// HandleNote renders the note page given by the "page" parameter, the first page without one.
+// Pages past the last one are shown as the last page.
+func (h *NoteHandler) HandleNote(c echo.Context) error {
+	return h.renderNote(c, 0)
+}
+
+// HandleNextNote renders the page after the "page" parameter, staying on the last page
+func (h *NoteHandler) HandleNextNote(c echo.Context) error {
+	return h.renderNote(c, 1)
+}
+
+// HandlePreviousNote renders the page before the "page" parameter, staying on the first page
+func (h *NoteHandler) HandlePreviousNote(c echo.Context) error {
+	return h.renderNote(c, -1)
+}
+
+// HandleEditNote renders the editor for a note page.
+// Editing the page after the last one adds a new page.
+func (h *NoteHandler) HandleEditNote(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, speaker, err := parseNoteScope(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		return err
+	}
+
+	note, err := h.noteRepo.GetNote(ctx, eventID, speaker, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get note: "+err.Error())
+	}
+	if page > note.TotalPages+1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Pages must be added one at a time")
+	}
+
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, noteTimerURL(eventID))
+	}
+
+	return components.NoteEditor(note).Render(ctx, c.Response().Writer)
+}
+
+// HandleSaveNote saves the content of a note page and renders it
+func (h *NoteHandler) HandleSaveNote(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, speaker, err := parseNoteScope(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		return err
+	}
+
+	note, err := h.noteRepo.GetNote(ctx, eventID, speaker, page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get note: "+err.Error())
+	}
+	if page > note.TotalPages+1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Pages must be added one at a time")
+	}
+
+	note.EventID = eventID
+	note.Speaker = speaker
+	note.PageNumber = page
+	note.Content = c.FormValue("content")
+	note.TotalPages = max(note.TotalPages, page)
+
+	if _, err := h.noteRepo.SaveNote(ctx, note); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save note: "+err.Error())
+	}
+
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, noteTimerURL(eventID))
+	}
+
+	return h.renderPanel(ctx, c, note)
+}
+
+// renderNote renders the note page offset pages away from the "page" parameter, kept within the existing pages
+func (h *NoteHandler) renderNote(c echo.Context, offset int) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, speaker, err := parseNoteScope(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, noteTimerURL(eventID))
+	}
+
+	note, err := h.loadNote(ctx, eventID, speaker, page+offset)
+	if err != nil {
+		return err
+	}
+
+	return h.renderPanel(ctx, c, note)
+}
+
+// renderPanel renders the note panel with the speakers of the note's event to pick from
+func (h *NoteHandler) renderPanel(ctx context.Context, c echo.Context, note domain.Note) error {
+	var speakers []domain.Speaker
+	if note.EventID != 0 {
+		event, err := h.eventRepo.GetEvent(ctx, note.EventID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get event: "+err.Error())
+		}
+		speakers = event.SpeakerList()
+	}
+
+	return components.NotePanel(note, speakers).Render(ctx, c.Response().Writer)
+}
+
+// loadNote returns the note for a page of an event and speaker, clamped between the first and the last page
+func (h *NoteHandler) loadNote(ctx context.Context, eventID uint, speaker string, page int) (domain.Note, error) {
+	page = max(page, 1)
+
+	note, err := h.noteRepo.GetNote(ctx, eventID, speaker, page)
+	if err != nil {
+		return domain.Note{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get note: "+err.Error())
+	}
+
+	if note.TotalPages > 0 && page > note.TotalPages {
+		note, err = h.noteRepo.GetNote(ctx, eventID, speaker, note.TotalPages)
+		if err != nil {
+			return domain.Note{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get note: "+err.Error())
+		}
+	}
+
+	return note, nil
+}
+
+// parseNoteScope reads the "event" and "speaker" parameters selecting whose notes are used.
+// Without an event the general notes are used.
+func parseNoteScope(c echo.Context) (uint, string, error) {
+	value := c.QueryParam("event")
+	if value == "" {
+		value = c.FormValue("event")
+	}
+
+	var eventID uint64
+	if value != "" {
+		var err error
+		eventID, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, "", echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+		}
+	}
+
+	speaker := c.QueryParam("speaker")
+	if speaker == "" {
+		speaker = c.FormValue("speaker")
+	}
+
+	return uint(eventID), strings.TrimSpace(speaker), nil
+}
+
+// noteTimerURL returns the timer page showing the notes of an event
+func noteTimerURL(eventID uint) string {
+	if eventID == 0 {
+		return "/timer"
+	}
+	return fmt.Sprintf("/timer?event=%d", eventID)
+}
+
+// parsePage reads the "page" parameter, defaulting to the first page
+func parsePage(c echo.Context) (int, error) {
+	value := c.QueryParam("page")
+	if value == "" {
+		value = c.FormValue("page")
+	}
+	if value == "" {
+		return 1, nil
+	}
+
+	page, err := strconv.Atoi(value)
+	if err != nil || page < 1 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Page must be a positive whole number")
+	}
+
+	return page, nil
+}
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// maxQuestionLength is the longest question an attendee can submit
+const maxQuestionLength = 1000
+
+// QuestionHandler handles question queue requests.
+// Every queue belongs to an event, selected by the "event" parameter and
+// defaulting to the event that is currently running.
+// Moderation routes live under /host and need the host key, see requireHost.
+// Every change is published on questionHub, so open queue pages update live.
+type QuestionHandler struct {
+	questionRepo repository.QuestionRepository
+	eventRepo    repository.EventRepository
+	questionHub  *pubsub.Hub[domain.QuestionEvent]
+	hostKey      string
+	clock        clock.Clock
+	// rateLimiter limits how many questions one client can submit
+	rateLimiter echo.MiddlewareFunc
+}
+
+// NewQuestionHandler creates a new question handler
+func NewQuestionHandler(questionRepo repository.QuestionRepository, eventRepo repository.EventRepository, questionHub *pubsub.Hub[domain.QuestionEvent], hostKey string, rateLimiter echo.MiddlewareFunc, clock clock.Clock) *QuestionHandler {
+	return &QuestionHandler{
+		questionRepo: questionRepo,
+		eventRepo:    eventRepo,
+		questionHub:  questionHub,
+		hostKey:      hostKey,
+		rateLimiter:  rateLimiter,
+		clock:        clock,
+	}
+}
+
+// RegisterRoutes registers the question routes
+func (h *QuestionHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/questions", h.HandleQuestionsPage)
+	e.POST("/questions/add", h.HandleAddQuestion, h.rateLimiter)
+	e.GET("/questions/list", h.HandleQuestionList)
+	e.GET("/questions/projector", h.HandleProjectorPage)
+	e.GET("/questions/events", h.HandleQuestionEvents)
+	e.POST("/questions/:id/vote", h.HandleUpvote)
+
+	host := e.Group("/host/questions", requireHost(h.hostKey))
+	host.GET("", h.HandleModerationPage)
+	host.GET("/list", h.HandleModerationList)
+	host.GET("/events", h.HandleModerationEvents)
+	host.POST("/:id/answer", h.HandleMarkAnswered)
+	host.POST("/:id/pin", h.HandlePin)
+	host.POST("/:id/hide", h.HandleHide)
+	host.POST("/:id/restore", h.HandleRestore)
+	host.POST("/:id/merge", h.HandleMerge)
+	host.GET("/:id/edit", h.HandleEditQuestion)
+	host.POST("/:id/edit", h.HandleSaveQuestion)
+}
+
+// HandleQuestionsPage renders the question queue with pending and answered questions
+func (h *QuestionHandler) HandleQuestionsPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	queue, events, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return pages.Questions(queue, events).Render(ctx, c.Response().Writer)
+}
+
+// HandleQuestionList renders just the question lists, so open pages can refresh the ranking
+func (h *QuestionHandler) HandleQuestionList(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	queue, _, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return pages.QuestionsContent(queue).Render(ctx, c.Response().Writer)
+}
+
+// HandleAddQuestion handles the submission of a new question by an attendee.
+// Attendees who ask anonymously don't give a name.
+func (h *QuestionHandler) HandleAddQuestion(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	// Parse form data
+	name := strings.TrimSpace(c.FormValue("name"))
+	content := strings.TrimSpace(c.FormValue("content"))
+	if c.FormValue("anonymous") == "on" {
+		name = ""
+	} else if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Name is required unless you ask anonymously")
+	}
+
+	// Validate required fields
+	if content == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Question is required")
+	}
+	if len(content) > maxQuestionLength {
+		return echo.NewHTTPError(http.StatusBadRequest, "Questions can be at most "+strconv.Itoa(maxQuestionLength)+" characters long")
+	}
+
+	event, _, err := h.selectEvent(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// Add question to repository
+	question, err := h.questionRepo.AddQuestion(ctx, domain.Question{
+		EventID: event.ID,
+		Name:    name,
+		Content: content,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add question: "+err.Error())
+	}
+	h.publish(domain.QuestionEventAdd, question)
+
+	return h.renderQuestions(ctx, c)
+}
+
+// HandleUpvote adds the vote of the current browser session to a question.
+// Voting again for the same question has no effect.
+func (h *QuestionHandler) HandleUpvote(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	question, err := h.loadQuestion(ctx, c)
+	if err != nil {
+		return err
+	}
+	if !question.IsVisible() {
+		return echo.NewHTTPError(http.StatusNotFound, "Question not found")
+	}
+	if !question.IsOpen() {
+		return echo.NewHTTPError(http.StatusConflict, "Answered questions can't be voted for")
+	}
+
+	voterID, err := sessionID(c)
+	if err != nil {
+		return err
+	}
+
+	voted, err := h.questionRepo.Upvote(ctx, question.ID, voterID)
+	if err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to vote: "+err.Error())
+	} else if err == nil && !voted {
+		return echo.NewHTTPError(http.StatusNotFound, "Question not found")
+	} else if voted {
+		question.Votes++
+		h.publish(domain.QuestionEventVote, question)
+	}
+
+	return h.renderQuestions(ctx, c)
+}
+
+// HandleProjectorPage renders the question queue for the projector, with the question
+// being answered now in large print and the top pending questions below it
+func (h *QuestionHandler) HandleProjectorPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	queue, _, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return pages.Projector(queue).Render(ctx, c.Response().Writer)
+}
+
+// HandleQuestionEvents streams the question queue of an event as Server-Sent Events.
+// Every change to one of its questions sends the re-rendered queue as out-of-band swaps,
+// for the projector with "view=projector" and for the queue page otherwise.
+func (h *QuestionHandler) HandleQuestionEvents(c echo.Context) error {
+	render := pages.QuestionsUpdate
+	if c.QueryParam("view") == "projector" {
+		render = pages.ProjectorUpdate
+	}
+
+	return h.streamQueue(c, render)
+}
+
+// streamQueue sends the question queue of the selected event, rendered by render, as a
+// Server-Sent Event whenever one of the event's questions changes
+func (h *QuestionHandler) streamQueue(c echo.Context, render func(queue components.QuestionQueue) templ.Component) error {
+	ctx := c.Request().Context()
+
+	event, _, err := h.selectEvent(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := h.questionHub.Subscribe()
+	defer unsubscribe()
+
+	// Load the queue before the stream starts, so a new session cookie still gets sent
+	queue, err := h.eventQueue(ctx, c, event)
+	if err != nil {
+		return err
+	}
+
+	startSSE(c)
+
+	// Send the current state first so the client starts in sync
+	if err := writeSSEComponent(ctx, c, "questions", render(queue)); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive.C:
+			if err := writeSSEKeepAlive(c); err != nil {
+				return err
+			}
+		case questionEvent, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if questionEvent.Question.EventID != event.ID {
+				continue
+			}
+
+			queue, err := h.eventQueue(ctx, c, event)
+			if err != nil {
+				log.Printf("Failed to get questions for event %d: %v\n", event.ID, err)
+				continue
+			}
+			if err := writeSSEComponent(ctx, c, "questions", render(queue)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish broadcasts a question change to all connected queue pages
+func (h *QuestionHandler) publish(eventType domain.QuestionEventType, question domain.Question) {
+	h.questionHub.Publish(domain.QuestionEvent{
+		Type:       eventType,
+		Question:   question,
+		OccurredAt: time.Now(),
+	})
+}
+
+// renderQuestions renders the question lists for HTMX requests and the full page otherwise
+func (h *QuestionHandler) renderQuestions(ctx context.Context, c echo.Context) error {
+	queue, events, err := h.loadQueue(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// Check if this is an HTMX request
+	if c.Request().Header.Get("HX-Request") == "true" {
+		// Return only the question lists for HTMX requests
+		return pages.QuestionsContent(queue).Render(ctx, c.Response().Writer)
+	}
+
+	// Return the full questions page for regular requests
+	return pages.Questions(queue, events).Render(ctx, c.Response().Writer)
+}
+
+// loadQuestion returns the question given by the "id" path parameter
+func (h *QuestionHandler) loadQuestion(ctx context.Context, c echo.Context) (domain.Question, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return domain.Question{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid question ID")
+	}
+
+	question, err := h.questionRepo.GetQuestion(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return domain.Question{}, echo.NewHTTPError(http.StatusNotFound, "Question not found")
+	} else if err != nil {
+		return domain.Question{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get question: "+err.Error())
+	}
+
+	return question, nil
+}
+
+// loadQueue returns the question queue of the selected event for the current browser session,
+// together with all events to choose from
+func (h *QuestionHandler) loadQueue(ctx context.Context, c echo.Context) (components.QuestionQueue, []domain.Event, error) {
+	event, events, err := h.selectEvent(ctx, c)
+	if err != nil {
+		return components.QuestionQueue{}, nil, err
+	}
+
+	queue, err := h.eventQueue(ctx, c, event)
+	if err != nil {
+		return components.QuestionQueue{}, nil, err
+	}
+
+	return queue, events, nil
+}
+
+// eventQueue returns the question queue of an event for the current browser session,
+// with the pending questions ranked by votes and the answered questions newest first
+func (h *QuestionHandler) eventQueue(ctx context.Context, c echo.Context, event domain.Event) (components.QuestionQueue, error) {
+	questions, err := h.questionRepo.GetRankedQuestions(ctx, event.ID)
+	if err != nil {
+		return components.QuestionQueue{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get questions: "+err.Error())
+	}
+
+	voterID, err := sessionID(c)
+	if err != nil {
+		return components.QuestionQueue{}, err
+	}
+
+	votedIDs, err := h.questionRepo.GetVotedQuestionIDs(ctx, voterID)
+	if err != nil {
+		return components.QuestionQueue{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get votes: "+err.Error())
+	}
+
+	queue := components.QuestionQueue{
+		Event:    event,
+		Pending:  make([]domain.Question, 0),
+		Answered: make([]domain.Question, 0),
+		Hidden:   make([]domain.Question, 0),
+		Voted:    make(map[uint]bool, len(votedIDs)),
+	}
+	for _, id := range votedIDs {
+		queue.Voted[id] = true
+	}
+	for _, question := range questions {
+		switch question.State {
+		case domain.QuestionStatePinned:
+			pinned := question
+			queue.NowAnswering = &pinned
+		case domain.QuestionStatePending:
+			queue.Pending = append(queue.Pending, question)
+		case domain.QuestionStateAnswered:
+			queue.Answered = append(queue.Answered, question)
+		default:
+			queue.Hidden = append(queue.Hidden, question)
+		}
+	}
+
+	// Pending questions keep the ranking they were loaded in
+	sort.SliceStable(queue.Answered, func(i, j int) bool {
+		return queue.Answered[i].SubmittedAt.After(queue.Answered[j].SubmittedAt)
+	})
+
+	return queue, nil
+}
+
+// selectEvent returns the event given by the "event" parameter, or the current event without one,
+// together with all events newest first. Event 0 stands for questions outside of any event.
+// Open slots nobody claimed yet have no talk to ask about, so they are left out.
+func (h *QuestionHandler) selectEvent(ctx context.Context, c echo.Context) (domain.Event, []domain.Event, error) {
+	upcomingEvents, err := h.eventRepo.GetUpcomingEvents(ctx)
+	if err != nil {
+		return domain.Event{}, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get upcoming events: "+err.Error())
+	}
+
+	pastEvents, err := h.eventRepo.GetPastEvents(ctx)
+	if err != nil {
+		return domain.Event{}, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get past events: "+err.Error())
+	}
+
+	events := make([]domain.Event, 0, len(upcomingEvents)+len(pastEvents))
+	for _, event := range append(upcomingEvents, pastEvents...) {
+		if !event.Open {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.After(events[j].Date)
+	})
+
+	value := c.QueryParam("event")
+	if value == "" {
+		value = c.FormValue("event")
+	}
+	if value == "" {
+		event, _ := domain.CurrentEvent(events, h.clock.Now())
+		return event, events, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return domain.Event{}, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+	if id == 0 {
+		return domain.Event{}, events, nil
+	}
+
+	for _, event := range events {
+		if event.ID == uint(id) {
+			return event, events, nil
+		}
+	}
+
+	return domain.Event{}, nil, echo.NewHTTPError(http.StatusNotFound, "Event not found")
+}
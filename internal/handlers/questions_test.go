@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+func TestSelectEventSkipsOpenSlots(t *testing.T) {
+	ctx := context.Background()
+	now := clock.Fixed(time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC))
+	eventRepo := mock.NewMockEventRepository(now, time.UTC)
+	h := NewQuestionHandler(mock.NewMockQuestionRepository(), eventRepo, pubsub.NewHub[domain.QuestionEvent](), "", questionRateLimiter(0, 0), now)
+
+	// An open slot tonight starts before the next sample talk a week from now
+	slotDate := time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC)
+	_, err := eventRepo.AddSeriesSlots(ctx, []domain.Event{{
+		Title:      "Open slot",
+		Date:       slotDate,
+		TimeZone:   "UTC",
+		Open:       true,
+		SeriesID:   1,
+		Occurrence: slotDate,
+	}})
+	if err != nil {
+		t.Fatalf("AddSeriesSlots: %v", err)
+	}
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/questions", nil), httptest.NewRecorder())
+	event, events, err := h.selectEvent(ctx, c)
+	if err != nil {
+		t.Fatalf("selectEvent: %v", err)
+	}
+
+	if event.Open || event.Title != "Generative AI for Scientific Discovery" {
+		t.Fatalf("current event: got %q, want the next talk", event.Title)
+	}
+	for _, e := range events {
+		if e.Open {
+			t.Fatalf("events offered for questions include open slot %d", e.ID)
+		}
+	}
+}
+
+func TestModerationEventsNeedHost(t *testing.T) {
+	e := newQuestionServer(3)
+
+	// The moderation stream includes hidden questions, so attendees can't follow it
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/host/questions/events?event=1", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// questionLimitMessage tells an attendee who hit the question limit what to do
+const questionLimitMessage = "You're asking faster than the host can answer. Please wait a moment before asking another question."
+
+// addressLimitFactor is how many clients' worth of questions one IP address can submit in total
+const addressLimitFactor = 10
+
+// questionRateLimiter returns middleware that limits how many questions one client can submit.
+// Clients are told apart by their session cookie together with their IP address, so attendees
+// sharing the venue Wi-Fi don't share a limit. Requests without a session cookie the server
+// signed share the limit of their IP address. Since anyone can get a new session cookie, an IP
+// address as a whole can submit at most addressLimitFactor times the limit of one client.
+// The IP address is the one echo's IPExtractor gives, so it can only be trusted as far as that
+// extractor trusts forwarding headers.
+// A limit of 0 questions per minute turns it off.
+func questionRateLimiter(perMinute float64, burst int) echo.MiddlewareFunc {
+	if perMinute <= 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	address := rateLimiter(perMinute*addressLimitFactor, max(burst, 1)*addressLimitFactor, func(c echo.Context) (string, error) {
+		return c.RealIP(), nil
+	})
+	client := rateLimiter(perMinute, max(burst, 1), func(c echo.Context) (string, error) {
+		id, ok := existingSessionID(c)
+		if !ok {
+			return "|" + c.RealIP(), nil
+		}
+		return id + "|" + c.RealIP(), nil
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return address(client(next))
+	}
+}
+
+// rateLimiter returns middleware that allows perMinute questions with a burst for each identifier
+// and shows the question limit message to clients past it
+func rateLimiter(perMinute float64, burst int, identifier middleware.Extractor) echo.MiddlewareFunc {
+	store := middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+		Rate:      rate.Limit(perMinute / 60),
+		Burst:     burst,
+		ExpiresIn: 10 * time.Minute,
+	})
+
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store:               store,
+		IdentifierExtractor: identifier,
+		ErrorHandler: func(c echo.Context, err error) error {
+			return err
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			if c.Request().Header.Get("HX-Request") != "true" {
+				return echo.NewHTTPError(http.StatusTooManyRequests, questionLimitMessage)
+			}
+
+			// Show the message next to the form instead of replacing the question lists
+			c.Response().Header().Set("HX-Retarget", "#question-form-error")
+			c.Response().Header().Set("HX-Reswap", "innerHTML")
+			c.Response().WriteHeader(http.StatusTooManyRequests)
+			return components.FormError(questionLimitMessage).Render(c.Request().Context(), c.Response().Writer)
+		},
+	})
+}
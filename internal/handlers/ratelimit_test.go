@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+// newQuestionServer returns a server with the question routes, allowing burst questions per client
+func newQuestionServer(burst int) *echo.Echo {
+	now := clock.Fixed(time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC))
+	h := NewQuestionHandler(
+		mock.NewMockQuestionRepository(),
+		mock.NewMockEventRepository(now, time.UTC),
+		pubsub.NewHub[domain.QuestionEvent](),
+		"",
+		questionRateLimiter(1, burst),
+		now,
+	)
+
+	// Read client addresses from the connection, as the server does without trusted proxies
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	h.RegisterRoutes(e)
+	return e
+}
+
+// postQuestion submits a question from remoteAddr with the given cookies and returns the response
+func postQuestion(e *echo.Echo, remoteAddr string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	return postQuestionForwarded(e, remoteAddr, "", cookies)
+}
+
+// postQuestionForwarded submits a question like postQuestion, claiming to be forwarded for the
+// address in forwardedFor when it isn't empty
+func postQuestionForwarded(e *echo.Echo, remoteAddr string, forwardedFor string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	form := url.Values{"name": {"Ada"}, "content": {"Why?"}}
+	req := httptest.NewRequest(http.MethodPost, "/questions/add", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestQuestionRateLimiterWithoutCookie(t *testing.T) {
+	e := newQuestionServer(3)
+
+	for i := 1; i <= 3; i++ {
+		if rec := postQuestion(e, "192.0.2.1:1234", nil); rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("question %d within the burst: got status %d", i, rec.Code)
+		}
+	}
+	if rec := postQuestion(e, "192.0.2.1:1234", nil); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("cookieless question past the burst: got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Other addresses have their own limit
+	if rec := postQuestion(e, "192.0.2.2:1234", nil); rec.Code == http.StatusTooManyRequests {
+		t.Fatalf("question from another address: got status %d", rec.Code)
+	}
+}
+
+func TestQuestionRateLimiterPerSession(t *testing.T) {
+	e := newQuestionServer(3)
+
+	first := postQuestion(e, "192.0.2.1:1234", nil)
+	cookies := first.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("no session cookie issued")
+	}
+
+	// The cookieless question counted against the address; the session has a limit of its own
+	for i := 1; i <= 3; i++ {
+		if rec := postQuestion(e, "192.0.2.1:1234", cookies); rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("question %d within the burst: got status %d", i, rec.Code)
+		}
+	}
+	if rec := postQuestion(e, "192.0.2.1:1234", cookies); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("question past the burst: got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Another attendee behind the same address isn't limited by the first one's session
+	other := postQuestion(e, "192.0.2.2:1234", nil).Result().Cookies()
+	if rec := postQuestion(e, "192.0.2.1:1234", other); rec.Code == http.StatusTooManyRequests {
+		t.Fatalf("question of another session: got status %d", rec.Code)
+	}
+}
+
+func TestQuestionRateLimiterWithMadeUpCookies(t *testing.T) {
+	e := newQuestionServer(3)
+
+	// Session cookies the server didn't issue share the limit of the address, however many there are
+	for i := 1; i <= 4; i++ {
+		id := strings.Repeat(strconv.Itoa(i), 32)
+		cookies := []*http.Cookie{{Name: sessionCookieName, Value: id + "." + strings.Repeat("0", 64)}}
+		rec := postQuestion(e, "192.0.2.1:1234", cookies)
+		if i <= 3 && rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("question %d within the burst: got status %d", i, rec.Code)
+		}
+		if i > 3 && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("question with a made up cookie past the burst: got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	}
+}
+
+func TestQuestionRateLimiterWithForwardedHeaders(t *testing.T) {
+	e := newQuestionServer(3)
+
+	// Made up forwarding headers don't give a client the limit of another address
+	for i := 1; i <= 4; i++ {
+		rec := postQuestionForwarded(e, "192.0.2.1:1234", "198.51.100."+strconv.Itoa(i), nil)
+		if i <= 3 && rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("question %d within the burst: got status %d", i, rec.Code)
+		}
+		if i > 3 && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("question with a forwarding header past the burst: got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	}
+}
+
+func TestQuestionRateLimiterPerAddress(t *testing.T) {
+	e := newQuestionServer(3)
+
+	// Every session has a limit of its own, but an address minting new sessions runs into the address limit
+	limit := 3 * addressLimitFactor
+	for i := 1; i <= limit+1; i++ {
+		id := fmt.Sprintf("%032x", i)
+		cookies := []*http.Cookie{{Name: sessionCookieName, Value: id + "." + sessionSignature(id)}}
+		rec := postQuestion(e, "192.0.2.1:1234", cookies)
+		if i <= limit && rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("question of session %d within the address limit: got status %d", i, rec.Code)
+		}
+		if i > limit && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("question past the address limit: got status %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	}
+
+	// Other addresses have their own limit
+	if rec := postQuestion(e, "192.0.2.2:1234", nil); rec.Code == http.StatusTooManyRequests {
+		t.Fatalf("question from another address: got status %d", rec.Code)
+	}
+}
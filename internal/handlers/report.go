@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+)
+
+// ReportHandler handles the planned vs actual timing report
+type ReportHandler struct {
+	eventRepo    repository.EventRepository
+	timerLogRepo repository.TimerLogRepository
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(eventRepo repository.EventRepository, timerLogRepo repository.TimerLogRepository) *ReportHandler {
+	return &ReportHandler{
+		eventRepo:    eventRepo,
+		timerLogRepo: timerLogRepo,
+	}
+}
+
+// RegisterRoutes registers the report routes
+func (h *ReportHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/report", h.HandleReportPage)
+	e.GET("/report.json", h.HandleReportJSON)
+}
+
+// HandleReportPage renders the planned vs actual duration of every timed event
+func (h *ReportHandler) HandleReportPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	timings, err := h.eventTimings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return pages.Report(timings).Render(ctx, c.Response().Writer)
+}
+
+// HandleReportJSON returns the planned vs actual duration of every timed event as JSON.
+// Durations are given in seconds.
+func (h *ReportHandler) HandleReportJSON(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	timings, err := h.eventTimings(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := make([]eventTimingResponse, len(timings))
+	for i, timing := range timings {
+		response[i] = newEventTimingResponse(timing)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// eventTimings computes the timing of every event that has timer log entries, newest event first
+func (h *ReportHandler) eventTimings(ctx context.Context) ([]domain.EventTiming, error) {
+	upcomingEvents, err := h.eventRepo.GetUpcomingEvents(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get upcoming events: "+err.Error())
+	}
+
+	pastEvents, err := h.eventRepo.GetPastEvents(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get past events: "+err.Error())
+	}
+
+	now := time.Now()
+	timings := make([]domain.EventTiming, 0)
+	for _, event := range append(upcomingEvents, pastEvents...) {
+		entries, err := h.timerLogRepo.GetEventTimerLog(ctx, event.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get timer log: "+err.Error())
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		timings = append(timings, domain.NewEventTiming(event, entries, now))
+	}
+
+	sort.Slice(timings, func(i, j int) bool {
+		return timings[i].Event.Date.After(timings[j].Event.Date)
+	})
+
+	return timings, nil
+}
+
+// eventTimingResponse is the JSON representation of an event's timing
+type eventTimingResponse struct {
+	EventID        uint                    `json:"event_id"`
+	Title          string                  `json:"title"`
+	Speaker        string                  `json:"speaker"`
+	Date           time.Time               `json:"date"`
+	TimeZone       string                  `json:"time_zone"`
+	PlannedSeconds int64                   `json:"planned_seconds"`
+	ActualSeconds  int64                   `json:"actual_seconds"`
+	OverrunSeconds int64                   `json:"overrun_seconds"`
+	Segments       []segmentTimingResponse `json:"segments"`
+}
+
+// segmentTimingResponse is the JSON representation of a segment's timing
+type segmentTimingResponse struct {
+	TimerID        uint   `json:"timer_id"`
+	Segment        string `json:"segment"`
+	PlannedSeconds int64  `json:"planned_seconds"`
+	ActualSeconds  int64  `json:"actual_seconds"`
+	OverrunSeconds int64  `json:"overrun_seconds"`
+	InProgress     bool   `json:"in_progress"`
+}
+
+// newEventTimingResponse converts an event timing to its JSON representation
+func newEventTimingResponse(timing domain.EventTiming) eventTimingResponse {
+	segments := make([]segmentTimingResponse, len(timing.Segments))
+	for i, segment := range timing.Segments {
+		segments[i] = segmentTimingResponse{
+			TimerID:        segment.TimerID,
+			Segment:        segment.Segment,
+			PlannedSeconds: seconds(segment.Planned),
+			ActualSeconds:  seconds(segment.Actual),
+			OverrunSeconds: seconds(segment.Overrun()),
+			InProgress:     segment.InProgress,
+		}
+	}
+
+	return eventTimingResponse{
+		EventID:        timing.Event.ID,
+		Title:          timing.Event.Title,
+		Speaker:        timing.Event.Speaker,
+		Date:           timing.Event.LocalDate(),
+		TimeZone:       timing.Event.TimeZone,
+		PlannedSeconds: seconds(timing.Planned),
+		ActualSeconds:  seconds(timing.Actual),
+		OverrunSeconds: seconds(timing.Overrun()),
+		Segments:       segments,
+	}
+}
+
+// seconds returns a duration in whole seconds
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
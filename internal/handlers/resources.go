@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// ResourceHandler handles the event pages and the resources attached to their talks
+type ResourceHandler struct {
+	resourceRepo repository.ResourceRepository
+	eventRepo    repository.EventRepository
+}
+
+// NewResourceHandler creates a new resource handler
+func NewResourceHandler(resourceRepo repository.ResourceRepository, eventRepo repository.EventRepository) *ResourceHandler {
+	return &ResourceHandler{
+		resourceRepo: resourceRepo,
+		eventRepo:    eventRepo,
+	}
+}
+
+// RegisterRoutes registers the resource routes
+func (h *ResourceHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/events/:id", h.HandleEventPage)
+	e.POST("/events/:id/resources", h.HandleAddResource)
+	e.POST("/resources/:id/delete", h.HandleDeleteResource)
+}
+
+// HandleEventPage renders an event with the resources of its talk
+func (h *ResourceHandler) HandleEventPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	event, err := loadEvent(ctx, c, h.eventRepo)
+	if err != nil {
+		return err
+	}
+
+	resources, err := h.resourceRepo.GetEventResources(ctx, event.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get resources: "+err.Error())
+	}
+
+	return pages.EventPage(event, resources).Render(ctx, c.Response().Writer)
+}
+
+// HandleAddResource attaches the resource of the add form to an event
+func (h *ResourceHandler) HandleAddResource(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	event, err := loadEvent(ctx, c, h.eventRepo)
+	if err != nil {
+		return err
+	}
+
+	resource, message := parseResourceForm(c)
+	if message != "" {
+		return formError(c, "#resource-form-error", message)
+	}
+	resource.EventID = event.ID
+
+	if _, err := h.resourceRepo.AddResource(ctx, resource); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add resource: "+err.Error())
+	}
+
+	return h.renderResources(ctx, c, event.ID)
+}
+
+// HandleDeleteResource removes a resource from its event
+func (h *ResourceHandler) HandleDeleteResource(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid resource ID")
+	}
+
+	resource, err := h.resourceRepo.GetResource(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Resource not found")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get resource: "+err.Error())
+	}
+
+	deleted, err := h.resourceRepo.DeleteResource(ctx, resource.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete resource: "+err.Error())
+	} else if !deleted {
+		return echo.NewHTTPError(http.StatusNotFound, "Resource not found")
+	}
+
+	return h.renderResources(ctx, c, resource.EventID)
+}
+
+// renderResources renders the resource list of an event for HTMX requests and redirects to
+// the event page otherwise
+func (h *ResourceHandler) renderResources(ctx context.Context, c echo.Context, eventID uint) error {
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/events/%d", eventID))
+	}
+
+	resources, err := h.resourceRepo.GetEventResources(ctx, eventID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get resources: "+err.Error())
+	}
+
+	return components.ResourceListUpdate(resources).Render(ctx, c.Response().Writer)
+}
+
+// parseResourceForm reads a resource from the fields of the add form, or returns why it can't
+func parseResourceForm(c echo.Context) (domain.Resource, string) {
+	title := strings.TrimSpace(c.FormValue("title"))
+	link := strings.TrimSpace(c.FormValue("url"))
+	if title == "" || link == "" {
+		return domain.Resource{}, "Title and URL are required"
+	}
+	if !isWebURL(link) {
+		return domain.Resource{}, "The URL has to be an http or https URL"
+	}
+
+	kind, ok := domain.ParseResourceKind(c.FormValue("kind"))
+	if !ok {
+		return domain.Resource{}, "Unknown resource type " + c.FormValue("kind")
+	}
+
+	return domain.Resource{
+		Kind:  kind,
+		Title: title,
+		URL:   link,
+		Notes: strings.TrimSpace(c.FormValue("notes")),
+	}, ""
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+)
+
+// slotHorizon is how far ahead series generate open slots
+const slotHorizon = 6 * 7 * 24 * time.Hour
+
+// slotGeneratorInterval is how often the slot generator adds the slots coming into the horizon
+const slotGeneratorInterval = time.Hour
+
+// SeriesHandler handles recurring session series and generates their open slots
+type SeriesHandler struct {
+	seriesRepo repository.SeriesRepository
+	eventRepo  repository.EventRepository
+	clock      clock.Clock
+	// timeZone is the group's timezone, preselected in the form of new series
+	timeZone *time.Location
+}
+
+// NewSeriesHandler creates a new series handler
+func NewSeriesHandler(seriesRepo repository.SeriesRepository, eventRepo repository.EventRepository, clock clock.Clock, timeZone *time.Location) *SeriesHandler {
+	return &SeriesHandler{
+		seriesRepo: seriesRepo,
+		eventRepo:  eventRepo,
+		clock:      clock,
+		timeZone:   timeZone,
+	}
+}
+
+// RegisterRoutes registers the series routes
+func (h *SeriesHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/series", h.HandleSeriesPage)
+	e.POST("/series/add", h.HandleAddSeries)
+	e.POST("/series/:id/delete", h.HandleDeleteSeries)
+}
+
+// HandleSeriesPage renders the series with the form for adding one
+func (h *SeriesHandler) HandleSeriesPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.seriesRepo.GetAllSeries(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get series: "+err.Error())
+	}
+
+	return pages.SeriesPage(series, h.timeZone.String()).Render(ctx, c.Response().Writer)
+}
+
+// HandleAddSeries adds a series and generates its slots for the coming weeks right away
+func (h *SeriesHandler) HandleAddSeries(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	series, message := parseSeriesForm(c)
+	if message != "" {
+		return formError(c, "#series-form-error", message)
+	}
+
+	series, err := h.seriesRepo.AddSeries(ctx, series)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add series: "+err.Error())
+	}
+
+	if _, err := h.generateSlots(ctx, series); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate slots: "+err.Error())
+	}
+
+	return h.renderSeries(ctx, c)
+}
+
+// HandleDeleteSeries ends a series and removes its open slots that haven't started yet.
+// Slots speakers claimed stay on the timeline.
+func (h *SeriesHandler) HandleDeleteSeries(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid series ID")
+	}
+
+	deleted, err := h.seriesRepo.DeleteSeries(ctx, uint(id))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete series: "+err.Error())
+	} else if !deleted {
+		return echo.NewHTTPError(http.StatusNotFound, "Series not found")
+	}
+
+	if _, err := h.eventRepo.DeleteOpenSlots(ctx, uint(id)); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete open slots: "+err.Error())
+	}
+
+	return h.renderSeries(ctx, c)
+}
+
+// RunSlotGenerator adds the open slots of every series within the horizon, at start and then
+// every hour, until ctx is cancelled. Slots that were generated before aren't added again.
+func (h *SeriesHandler) RunSlotGenerator(ctx context.Context) {
+	ticker := time.NewTicker(slotGeneratorInterval)
+	defer ticker.Stop()
+
+	for {
+		h.generateAllSlots(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// generateAllSlots adds the missing slots of every series, logging failures
+func (h *SeriesHandler) generateAllSlots(ctx context.Context) {
+	allSeries, err := h.seriesRepo.GetAllSeries(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to get series: %v\n", err)
+		}
+		return
+	}
+
+	for _, series := range allSeries {
+		added, err := h.generateSlots(ctx, series)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to generate slots of series %d: %v\n", series.ID, err)
+			}
+			continue
+		}
+		if added > 0 {
+			log.Printf("Added %d open slots to series %q\n", added, series.Title)
+		}
+	}
+}
+
+// generateSlots adds the missing slots of a series from now until the horizon
+func (h *SeriesHandler) generateSlots(ctx context.Context, series domain.Series) (int, error) {
+	now := h.clock.Now()
+	return h.eventRepo.AddSeriesSlots(ctx, series.Slots(now, now.Add(slotHorizon)))
+}
+
+// renderSeries renders the series list for HTMX requests and redirects to the series page otherwise
+func (h *SeriesHandler) renderSeries(ctx context.Context, c echo.Context) error {
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, "/series")
+	}
+
+	series, err := h.seriesRepo.GetAllSeries(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get series: "+err.Error())
+	}
+
+	return components.SeriesListUpdate(series).Render(ctx, c.Response().Writer)
+}
+
+// parseSeriesForm reads a series from the fields of the series form, or returns why it can't
+func parseSeriesForm(c echo.Context) (domain.Series, string) {
+	title := c.FormValue("title")
+	dateStr := c.FormValue("date")
+	timeStr := c.FormValue("time")
+	timeZone := c.FormValue("time_zone")
+	if title == "" || dateStr == "" || timeStr == "" || timeZone == "" {
+		return domain.Series{}, "Title, first date, time and timezone are required"
+	}
+
+	location, err := domain.LoadTimeZone(timeZone)
+	if err != nil {
+		return domain.Series{}, "Unknown timezone " + timeZone
+	}
+
+	// The first session sets the weekday and the time of day of the series
+	first, err := time.ParseInLocation("2006-01-02T15:04", dateStr+"T"+timeStr, location)
+	if err != nil {
+		return domain.Series{}, "Invalid date or time format"
+	}
+
+	interval, err := strconv.Atoi(c.FormValue("interval"))
+	if err != nil || interval < 1 || interval > 52 {
+		return domain.Series{}, "Repeat every 1 to 52 weeks"
+	}
+
+	minutes, err := strconv.Atoi(c.FormValue("duration"))
+	if err != nil || minutes < 1 || minutes > 24*60 {
+		return domain.Series{}, "Sessions take between 1 minute and 24 hours"
+	}
+
+	series := domain.Series{
+		Title:    title,
+		First:    first,
+		TimeZone: location.String(),
+		Interval: interval,
+		Duration: time.Duration(minutes) * time.Minute,
+	}
+
+	// The last day is included, so the series runs until the end of it
+	if untilStr := c.FormValue("until"); untilStr != "" {
+		until, err := time.ParseInLocation("2006-01-02", untilStr, location)
+		if err != nil {
+			return domain.Series{}, "Invalid end date"
+		}
+		series.Until = until.AddDate(0, 0, 1).Add(-time.Second)
+		if series.Until.Before(first) {
+			return domain.Series{}, "The series has to end after its first session"
+		}
+	}
+
+	return series, ""
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sessionCookieName is the cookie identifying a browser session
+const sessionCookieName = "aia_session"
+
+// sessionKey signs the session IDs this server issues, so clients can't make up their own.
+// It is random per process, so sessions end when the server restarts.
+var sessionKey = newSessionKey()
+
+// sessionID returns the ID of the browser session making the request.
+// A new ID is issued in a signed session cookie, which the browser drops when it closes,
+// if the request doesn't carry a valid one yet.
+func sessionID(c echo.Context) (string, error) {
+	if id, ok := existingSessionID(c); ok {
+		return id, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to create session: "+err.Error())
+	}
+	id := hex.EncodeToString(buf)
+	value := id + "." + sessionSignature(id)
+
+	c.SetCookie(&http.Cookie{
+		Name:     sessionCookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Later calls during this request see the new session
+	c.Request().AddCookie(&http.Cookie{Name: sessionCookieName, Value: value})
+
+	return id, nil
+}
+
+// existingSessionID returns the ID of the session cookie the request carries, and false if it
+// carries none or one that wasn't issued by sessionID
+func existingSessionID(c echo.Context) (string, bool) {
+	cookie, err := c.Cookie(sessionCookieName)
+	if err != nil {
+		return "", false
+	}
+
+	id, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok || len(id) != 32 || !hmac.Equal([]byte(signature), []byte(sessionSignature(id))) {
+		return "", false
+	}
+	return id, true
+}
+
+// sessionSignature returns the signature the session cookie holds next to id
+func sessionSignature(id string) string {
+	mac := hmac.New(sha256.New, sessionKey)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSessionKey returns a random key to sign session IDs with
+func newSessionKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("failed to create session key: " + err.Error())
+	}
+	return key
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// speakerSuggestionLimit is the number of speakers suggested while typing a speaker's name
+const speakerSuggestionLimit = 8
+
+// SpeakerHandler handles speaker profiles and speaker suggestions
+type SpeakerHandler struct {
+	speakerRepo repository.SpeakerRepository
+	eventRepo   repository.EventRepository
+	// clock tells a speaker's upcoming talks from their past ones
+	clock clock.Clock
+}
+
+// NewSpeakerHandler creates a new speaker handler
+func NewSpeakerHandler(speakerRepo repository.SpeakerRepository, eventRepo repository.EventRepository, clock clock.Clock) *SpeakerHandler {
+	return &SpeakerHandler{
+		speakerRepo: speakerRepo,
+		eventRepo:   eventRepo,
+		clock:       clock,
+	}
+}
+
+// RegisterRoutes registers the speaker routes
+func (h *SpeakerHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/speakers", h.HandleSpeakersPage)
+	e.GET("/speakers/suggest", h.HandleSuggestSpeakers)
+	e.GET("/speakers/:id", h.HandleSpeakerPage)
+	e.POST("/speakers/:id/edit", h.HandleEditSpeaker)
+}
+
+// HandleSpeakersPage renders the list of all speakers
+func (h *SpeakerHandler) HandleSpeakersPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	speakers, err := h.speakerRepo.GetAllSpeakers(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get speakers: "+err.Error())
+	}
+
+	return pages.Speakers(speakers).Render(ctx, c.Response().Writer)
+}
+
+// HandleSuggestSpeakers renders the speakers whose name contains the "speaker" parameter,
+// which is the value of the speaker field being typed in
+func (h *SpeakerHandler) HandleSuggestSpeakers(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	speakers, err := h.speakerRepo.SearchSpeakers(ctx, c.QueryParam("speaker"), speakerSuggestionLimit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search speakers: "+err.Error())
+	}
+
+	return components.SpeakerSuggestions(speakers).Render(ctx, c.Response().Writer)
+}
+
+// HandleSpeakerPage renders the profile of a speaker with their upcoming and past talks
+func (h *SpeakerHandler) HandleSpeakerPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	speaker, err := h.loadSpeaker(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.eventRepo.GetSpeakerEvents(ctx, speaker.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get speaker events: "+err.Error())
+	}
+
+	// Events come soonest first; past talks are shown newest first
+	now := h.clock.Now()
+	var upcoming, past []domain.Event
+	for _, event := range events {
+		if event.IsOver(now) {
+			past = append([]domain.Event{event}, past...)
+		} else {
+			upcoming = append(upcoming, event)
+		}
+	}
+
+	return pages.SpeakerPage(speaker, upcoming, past).Render(ctx, c.Response().Writer)
+}
+
+// HandleEditSpeaker saves the profile form of a speaker
+func (h *SpeakerHandler) HandleEditSpeaker(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	existing, err := h.loadSpeaker(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	speaker, message := parseSpeakerForm(c)
+	if message != "" {
+		return formError(c, "#speaker-form-error", message)
+	}
+	speaker.ID = existing.ID
+
+	updated, err := h.speakerRepo.UpdateSpeaker(ctx, speaker)
+	if errors.Is(err, repository.ErrConflict) {
+		return formError(c, "#speaker-form-error", "There is another speaker named "+speaker.Name)
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update speaker: "+err.Error())
+	} else if !updated {
+		return echo.NewHTTPError(http.StatusNotFound, "Speaker not found")
+	}
+
+	// Check if this is an HTMX request
+	if c.Request().Header.Get("HX-Request") != "true" {
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/speakers/%d", speaker.ID))
+	}
+
+	return components.SpeakerProfile(speaker).Render(ctx, c.Response().Writer)
+}
+
+// loadSpeaker returns the speaker given by the "id" path parameter
+func (h *SpeakerHandler) loadSpeaker(ctx context.Context, c echo.Context) (domain.Speaker, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return domain.Speaker{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid speaker ID")
+	}
+
+	speaker, err := h.speakerRepo.GetSpeaker(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return domain.Speaker{}, echo.NewHTTPError(http.StatusNotFound, "Speaker not found")
+	} else if err != nil {
+		return domain.Speaker{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get speaker: "+err.Error())
+	}
+
+	return speaker, nil
+}
+
+// parseSpeakerForm reads a speaker from the fields of the profile form, or returns why it can't
+func parseSpeakerForm(c echo.Context) (domain.Speaker, string) {
+	speakers := domain.SpeakersNamed([]string{c.FormValue("name")})
+	if len(speakers) == 0 {
+		return domain.Speaker{}, "The name is required"
+	}
+	speaker := speakers[0]
+	if strings.ContainsAny(speaker.Name, ",&") {
+		return domain.Speaker{}, "Names can't contain commas or ampersands, which separate co-speakers"
+	}
+	speaker.Bio = strings.TrimSpace(c.FormValue("bio"))
+
+	for _, link := range strings.Split(c.FormValue("links"), "\n") {
+		link = strings.TrimSpace(link)
+		if link == "" {
+			continue
+		}
+		if !isWebURL(link) {
+			return domain.Speaker{}, "Links have to be http or https URLs: " + link
+		}
+		speaker.Links = append(speaker.Links, link)
+	}
+
+	speaker.AvatarURL = strings.TrimSpace(c.FormValue("avatar_url"))
+	if speaker.AvatarURL != "" && !isWebURL(speaker.AvatarURL) {
+		return domain.Speaker{}, "The avatar has to be an http or https URL"
+	}
+
+	return speaker, ""
+}
+
+// isWebURL reports whether link is an absolute http or https URL
+func isWebURL(link string) bool {
+	parsed, err := url.Parse(link)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
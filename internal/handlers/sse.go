@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// sseKeepAliveInterval is how often an idle event stream receives a keep-alive
+const sseKeepAliveInterval = 15 * time.Second
+
+// startSSE writes the Server-Sent Events response headers and flushes them to the client
+func startSSE(c echo.Context) {
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+}
+
+// writeSSEComponent renders a component and sends it as a named Server-Sent Event
+func writeSSEComponent(ctx context.Context, c echo.Context, event string, component templ.Component) error {
+	var buf bytes.Buffer
+	if err := component.Render(ctx, &buf); err != nil {
+		return fmt.Errorf("failed to render %s event: %w", event, err)
+	}
+
+	w := c.Response()
+	if _, err := fmt.Fprintf(w, "event: %s\n", event); err != nil {
+		return err
+	}
+	// Every line of the payload needs its own data field
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if _, err := fmt.Fprintf(w, "data: %s\n", line); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprint(w, "\n"); err != nil {
+		return err
+	}
+	w.Flush()
+
+	return nil
+}
+
+// writeSSEKeepAlive sends a comment line so proxies don't close an idle stream
+func writeSSEKeepAlive(c echo.Context) error {
+	w := c.Response()
+	if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	w.Flush()
+
+	return nil
+}
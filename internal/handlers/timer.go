@@ -0,0 +1,564 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/components"
+	"github.com/go-go-golems/ai-in-action-app/internal/templates/pages"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// defaultSegmentDuration is the duration suggested for the first timer of an event without timers
+const defaultSegmentDuration = 15 * time.Minute
+
+// TimerHandler handles timer-related requests
+type TimerHandler struct {
+	timerRepo    repository.TimerRepository
+	agendaRepo   repository.AgendaRepository
+	timerLogRepo repository.TimerLogRepository
+	timerHub     *pubsub.Hub[domain.TimerEvent]
+
+	// onExpire is called for every timer that runs out, see OnExpire
+	onExpire []func(ctx context.Context, timer domain.Timer)
+
+	// watched counts the open event streams per timer ID, so ticks are only published for timers someone is viewing
+	watched   map[uint]int
+	watchedMu sync.Mutex
+}
+
+// NewTimerHandler creates a new timer handler
+func NewTimerHandler(timerRepo repository.TimerRepository, agendaRepo repository.AgendaRepository, timerLogRepo repository.TimerLogRepository, timerHub *pubsub.Hub[domain.TimerEvent]) *TimerHandler {
+	return &TimerHandler{
+		timerRepo:    timerRepo,
+		agendaRepo:   agendaRepo,
+		timerLogRepo: timerLogRepo,
+		timerHub:     timerHub,
+		watched:      make(map[uint]int),
+	}
+}
+
+// RegisterRoutes registers the timer routes
+func (h *TimerHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/timer", h.HandleTimerPage)
+	e.GET("/timer/display", h.HandleTimerDisplay)
+	e.GET("/timer/events", h.HandleTimerEvents)
+	e.POST("/timer/segments", h.HandleAddSegment)
+	e.POST("/timer/start", h.HandleTimerStart)
+	e.POST("/timer/pause", h.HandleTimerPause)
+	e.POST("/timer/resume", h.HandleTimerResume)
+	e.POST("/timer/reset", h.HandleTimerReset)
+	e.POST("/timer/add", h.HandleTimerAdd)
+	e.POST("/timer/subtract", h.HandleTimerSubtract)
+	e.POST("/timer/warnings", h.HandleTimerWarnings)
+}
+
+// OnExpire registers a function that is called with every timer that runs out.
+// Unlike subscribers of the timer hub, which may miss events, it is called for every expiry.
+func (h *TimerHandler) OnExpire(fn func(ctx context.Context, timer domain.Timer)) {
+	h.onExpire = append(h.onExpire, fn)
+}
+
+// HandleTimerPage renders the timer and notes page.
+// With an event parameter it shows the timer of the event's current agenda segment,
+// or its first timer without an agenda. An event without that timer gets a form to create it,
+// as viewing the page never creates timers.
+func (h *TimerHandler) HandleTimerPage(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	if c.QueryParam("event") == "" {
+		timer, err := h.loadTimer(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		return h.renderTimerPage(ctx, c, timer)
+	}
+
+	eventID, err := strconv.ParseUint(c.QueryParam("event"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	var timer domain.Timer
+	if segment := c.QueryParam("segment"); segment != "" {
+		timer, err = h.timerRepo.GetEventTimer(ctx, uint(eventID), segment)
+		if errors.Is(err, repository.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Timer not found")
+		} else if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get timer: "+err.Error())
+		}
+	} else if agenda, err := h.agendaRepo.GetAgenda(ctx, uint(eventID)); err == nil {
+		current, _ := agenda.CurrentSegment()
+		timer, err = h.timerRepo.GetEventTimer(ctx, uint(eventID), current.Name)
+		if errors.Is(err, repository.ErrNotFound) {
+			return pages.NewEventTimer(agenda, current).Render(ctx, c.Response().Writer)
+		} else if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get timer: "+err.Error())
+		}
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get agenda: "+err.Error())
+	} else {
+		timers, err := h.timerRepo.GetEventTimers(ctx, uint(eventID))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get event timers: "+err.Error())
+		}
+
+		if len(timers) == 0 {
+			agenda := domain.Agenda{EventID: uint(eventID)}
+			segment := domain.AgendaSegment{Name: "talk", Duration: defaultSegmentDuration}
+			return pages.NewEventTimer(agenda, segment).Render(ctx, c.Response().Writer)
+		}
+		timer = timers[0]
+	}
+
+	return h.renderTimerPage(ctx, c, timer)
+}
+
+// HandleTimerDisplay renders the current timer state as an HTML fragment
+func (h *TimerHandler) HandleTimerDisplay(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	timer, err := h.loadTimer(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	return components.TimerDisplay(timer).Render(ctx, c.Response().Writer)
+}
+
+// HandleTimerStart starts the timer, restarting from the full duration if it has expired
+func (h *TimerHandler) HandleTimerStart(c echo.Context) error {
+	return h.transitionTimer(c, domain.TimerEventStart, func(timer *domain.Timer, now time.Time) error {
+		return timer.Start(now)
+	})
+}
+
+// HandleTimerPause pauses a running timer
+func (h *TimerHandler) HandleTimerPause(c echo.Context) error {
+	return h.transitionTimer(c, domain.TimerEventPause, func(timer *domain.Timer, now time.Time) error {
+		return timer.Pause(now)
+	})
+}
+
+// HandleTimerResume resumes a paused timer
+func (h *TimerHandler) HandleTimerResume(c echo.Context) error {
+	return h.transitionTimer(c, domain.TimerEventResume, func(timer *domain.Timer, now time.Time) error {
+		return timer.Resume(now)
+	})
+}
+
+// HandleTimerReset stops the timer and resets it to the given duration in minutes.
+// Without a duration the timer is reset to its current duration.
+func (h *TimerHandler) HandleTimerReset(c echo.Context) error {
+	var duration time.Duration
+	if c.FormValue("minutes") != "" {
+		minutes, err := parseMinutes(c)
+		if err != nil {
+			return err
+		}
+		duration = time.Duration(minutes) * time.Minute
+	}
+
+	return h.transitionTimer(c, domain.TimerEventReset, func(timer *domain.Timer, now time.Time) error {
+		if duration == 0 {
+			duration = timer.Duration
+		}
+		timer.Reset(duration)
+		return nil
+	})
+}
+
+// HandleTimerAdd adds minutes to the remaining time
+func (h *TimerHandler) HandleTimerAdd(c echo.Context) error {
+	minutes, err := parseMinutes(c)
+	if err != nil {
+		return err
+	}
+
+	return h.transitionTimer(c, domain.TimerEventAdjust, func(timer *domain.Timer, now time.Time) error {
+		return timer.Adjust(time.Duration(minutes)*time.Minute, now)
+	})
+}
+
+// HandleTimerSubtract subtracts minutes from the remaining time
+func (h *TimerHandler) HandleTimerSubtract(c echo.Context) error {
+	minutes, err := parseMinutes(c)
+	if err != nil {
+		return err
+	}
+
+	return h.transitionTimer(c, domain.TimerEventAdjust, func(timer *domain.Timer, now time.Time) error {
+		return timer.Adjust(-time.Duration(minutes)*time.Minute, now)
+	})
+}
+
+// HandleTimerWarnings sets the warning thresholds from a comma separated list of minutes
+func (h *TimerHandler) HandleTimerWarnings(c echo.Context) error {
+	thresholds := make([]time.Duration, 0)
+	for _, part := range strings.Split(c.FormValue("warnings"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		minutes, err := strconv.ParseFloat(part, 64)
+		threshold := time.Duration(minutes * float64(time.Minute)).Round(time.Second)
+		if err != nil || threshold < time.Second {
+			return echo.NewHTTPError(http.StatusBadRequest, "Warnings must be a comma separated list of positive minutes")
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	return h.transitionTimer(c, domain.TimerEventConfig, func(timer *domain.Timer, now time.Time) error {
+		return timer.SetWarningThresholds(thresholds)
+	})
+}
+
+// transitionTimer loads the selected timer, applies a state transition, saves it and
+// broadcasts the change. Illegal transitions and lost races are reported as conflicts.
+func (h *TimerHandler) transitionTimer(c echo.Context, eventType domain.TimerEventType, transition func(timer *domain.Timer, now time.Time) error) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	timer, err := h.loadTimer(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if err := transition(&timer, time.Now()); err != nil {
+		if errors.Is(err, domain.ErrInvalidTimerTransition) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update timer: "+err.Error())
+	}
+
+	updated, err := h.timerRepo.UpdateTimer(ctx, timer)
+	if errors.Is(err, repository.ErrConflict) {
+		return echo.NewHTTPError(http.StatusConflict, "The timer was changed by someone else, please try again")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update timer: "+err.Error())
+	} else if !updated {
+		return echo.NewHTTPError(http.StatusNotFound, "Timer not found")
+	}
+	timer.Version++
+
+	h.publish(ctx, eventType, timer)
+	return h.renderTimer(ctx, c, timer)
+}
+
+// HandleAddSegment creates a timer for a new segment of an event and redirects to it
+func (h *TimerHandler) HandleAddSegment(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	eventID, err := strconv.ParseUint(c.FormValue("event"), 10, 64)
+	if err != nil || eventID == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid event ID")
+	}
+
+	segment := strings.TrimSpace(c.FormValue("segment"))
+	if segment == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Segment name is required")
+	}
+
+	minutes, err := parseMinutes(c)
+	if err != nil {
+		return err
+	}
+
+	timer, err := h.timerRepo.CreateEventTimer(ctx, uint(eventID), segment, time.Duration(minutes)*time.Minute)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return echo.NewHTTPError(http.StatusConflict, "This event already has a "+segment+" timer")
+	} else if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create timer: "+err.Error())
+	}
+
+	timerURL := fmt.Sprintf("/timer?timer=%d", timer.ID)
+	if c.Request().Header.Get("HX-Request") == "true" {
+		c.Response().Header().Set("HX-Redirect", timerURL)
+		return c.NoContent(http.StatusCreated)
+	}
+
+	return c.Redirect(http.StatusSeeOther, timerURL)
+}
+
+// HandleTimerEvents streams state changes of a timer to the client as Server-Sent Events.
+// When the timer's agenda moves to another segment the stream follows the timer of that segment.
+func (h *TimerHandler) HandleTimerEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	timer, err := h.loadTimer(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := h.timerHub.Subscribe()
+	defer unsubscribe()
+
+	unwatch := h.watch(timer.ID)
+	defer func() {
+		unwatch()
+	}()
+
+	startSSE(c)
+
+	// Send the current state first so the client starts in sync
+	if err := writeSSEComponent(ctx, c, "timer", components.TimerDisplay(timer)); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive.C:
+			if err := writeSSEKeepAlive(c); err != nil {
+				return err
+			}
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Type == domain.TimerEventAdvance && timer.EventID != 0 && event.Timer.EventID == timer.EventID && event.Timer.ID != timer.ID {
+				unwatch()
+				unwatch = h.watch(event.Timer.ID)
+				timer = event.Timer
+
+				agenda, err := h.agendaRepo.GetAgenda(ctx, timer.EventID)
+				if err != nil {
+					log.Printf("Failed to get agenda for event %d: %v\n", timer.EventID, err)
+				} else if err := writeSSEComponent(ctx, c, "agenda", components.AgendaPanel(agenda, timer)); err != nil {
+					return err
+				}
+			}
+			if event.Timer.ID != timer.ID {
+				continue
+			}
+			// Ticks only update the clock, so the timer forms keep what is being typed
+			if event.Type == domain.TimerEventTick {
+				if err := writeSSEComponent(ctx, c, "timer-clock", components.TimerClock(event.Timer)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := writeSSEComponent(ctx, c, "timer", components.TimerDisplay(event.Timer)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunTicker checks every second whether a running timer ran out, whether or not anyone is
+// viewing it, and publishes a tick for each watched timer that is counting. It returns when
+// ctx is cancelled.
+func (h *TimerHandler) RunTicker(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		h.expireTimers(ctx)
+
+		for _, id := range h.watchedTimerIDs() {
+			timer, err := h.timerRepo.GetTimerByID(ctx, id)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to get timer %d for tick: %v\n", id, err)
+				}
+				continue
+			}
+
+			if timer.IsRunning() {
+				h.publish(ctx, domain.TimerEventTick, timer)
+			}
+		}
+	}
+}
+
+// expireTimers saves the running timers that ran out as being in overtime, publishes an
+// expire event for each and passes them to the OnExpire functions. Saving the overtime state
+// records the expiry, so it is handled once per timer, even across restarts. Timers changed
+// meanwhile are checked again next time.
+func (h *TimerHandler) expireTimers(ctx context.Context) {
+	timers, err := h.timerRepo.GetRunningTimers(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to get running timers: %v\n", err)
+		}
+		return
+	}
+
+	for _, timer := range timers {
+		if timer.State != domain.TimerStateOvertime {
+			continue
+		}
+
+		updated, err := h.timerRepo.UpdateTimer(ctx, timer)
+		if errors.Is(err, repository.ErrConflict) || (err == nil && !updated) {
+			continue
+		} else if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to expire timer %d: %v\n", timer.ID, err)
+			}
+			continue
+		}
+		timer.Version++
+
+		h.publish(ctx, domain.TimerEventExpire, timer)
+		for _, fn := range h.onExpire {
+			fn(ctx, timer)
+		}
+	}
+}
+
+// watch marks a timer as viewed by an event stream and returns a function that undoes it
+func (h *TimerHandler) watch(id uint) func() {
+	h.watchedMu.Lock()
+	h.watched[id]++
+	h.watchedMu.Unlock()
+
+	return func() {
+		h.watchedMu.Lock()
+		defer h.watchedMu.Unlock()
+
+		h.watched[id]--
+		if h.watched[id] <= 0 {
+			delete(h.watched, id)
+		}
+	}
+}
+
+// watchedTimerIDs returns the IDs of all timers viewed by at least one event stream
+func (h *TimerHandler) watchedTimerIDs() []uint {
+	h.watchedMu.Lock()
+	defer h.watchedMu.Unlock()
+
+	ids := make([]uint, 0, len(h.watched))
+	for id := range h.watched {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// publish records a timer state change in the timer log and broadcasts it to all connected clients
+func (h *TimerHandler) publish(ctx context.Context, eventType domain.TimerEventType, timer domain.Timer) {
+	publishTimerEvent(ctx, h.timerHub, h.timerLogRepo, eventType, timer)
+}
+
+// publishTimerEvent records a timer state change in the timer log and broadcasts it on the hub.
+// The change is already saved, so failing to log it is reported but doesn't stop the broadcast.
+func publishTimerEvent(ctx context.Context, timerHub *pubsub.Hub[domain.TimerEvent], timerLogRepo repository.TimerLogRepository, eventType domain.TimerEventType, timer domain.Timer) {
+	event := domain.TimerEvent{
+		Type:       eventType,
+		Timer:      timer,
+		OccurredAt: time.Now(),
+	}
+
+	if eventType.IsLogged() {
+		_, err := timerLogRepo.AddTimerLogEntry(ctx, domain.TimerLogEntry{
+			TimerID:       timer.ID,
+			EventID:       timer.EventID,
+			Segment:       timer.Segment,
+			Type:          eventType,
+			Duration:      timer.Duration,
+			RemainingTime: timer.RemainingTime,
+			Overtime:      timer.Overtime,
+			OccurredAt:    event.OccurredAt,
+		})
+		if err != nil {
+			log.Printf("Failed to log %s of timer %d: %v\n", eventType, timer.ID, err)
+		}
+	}
+
+	timerHub.Publish(event)
+}
+
+// loadTimer returns the timer selected by the "timer" parameter, or the default timer without one
+func (h *TimerHandler) loadTimer(ctx context.Context, c echo.Context) (domain.Timer, error) {
+	if c.QueryParam("timer") == "" && c.FormValue("timer") == "" {
+		timer, err := h.timerRepo.GetTimer(ctx)
+		if err != nil {
+			return domain.Timer{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get timer: "+err.Error())
+		}
+		return timer, nil
+	}
+
+	idStr := c.QueryParam("timer")
+	if idStr == "" {
+		idStr = c.FormValue("timer")
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return domain.Timer{}, echo.NewHTTPError(http.StatusBadRequest, "Invalid timer ID")
+	}
+
+	timer, err := h.timerRepo.GetTimerByID(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return domain.Timer{}, echo.NewHTTPError(http.StatusNotFound, "Timer not found")
+	} else if err != nil {
+		return domain.Timer{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get timer: "+err.Error())
+	}
+
+	return timer, nil
+}
+
+// renderTimer renders the timer fragment for HTMX requests and the full page otherwise
+func (h *TimerHandler) renderTimer(ctx context.Context, c echo.Context, timer domain.Timer) error {
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.TimerDisplay(timer).Render(ctx, c.Response().Writer)
+	}
+
+	return h.renderTimerPage(ctx, c, timer)
+}
+
+// renderTimerPage renders the full timer page together with the other timers and the agenda of the same event
+func (h *TimerHandler) renderTimerPage(ctx context.Context, c echo.Context, timer domain.Timer) error {
+	var segments []domain.Timer
+	agenda := domain.Agenda{EventID: timer.EventID}
+	if timer.EventID != 0 {
+		var err error
+		segments, err = h.timerRepo.GetEventTimers(ctx, timer.EventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get event timers: "+err.Error())
+		}
+
+		agenda, err = h.agendaRepo.GetAgenda(ctx, timer.EventID)
+		if errors.Is(err, repository.ErrNotFound) {
+			agenda = domain.Agenda{EventID: timer.EventID}
+		} else if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get agenda: "+err.Error())
+		}
+	}
+
+	return pages.Timer(timer, segments, agenda).Render(ctx, c.Response().Writer)
+}
+
+// parseMinutes reads a positive "minutes" form value
+func parseMinutes(c echo.Context) (int, error) {
+	minutes, err := strconv.Atoi(c.FormValue("minutes"))
+	if err != nil || minutes <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Minutes must be a positive whole number")
+	}
+
+	return minutes, nil
+}
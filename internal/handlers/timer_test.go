@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/pubsub"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository/mock"
+	"github.com/labstack/echo/v4"
+)
+
+// startTimerAgo creates a timer for an event segment and saves it as started ago
+func startTimerAgo(t *testing.T, timerRepo *mock.MockTimerRepository, eventID uint, segment string, duration time.Duration, ago time.Duration) domain.Timer {
+	t.Helper()
+	ctx := context.Background()
+
+	timer, err := timerRepo.CreateEventTimer(ctx, eventID, segment, duration)
+	if err != nil {
+		t.Fatalf("CreateEventTimer: %v", err)
+	}
+	if err := timer.Start(time.Now().Add(-ago)); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timerRepo.UpdateTimer(ctx, timer); err != nil {
+		t.Fatalf("UpdateTimer: %v", err)
+	}
+	timer.Version++
+	return timer
+}
+
+// countLogged returns how many entries of type eventType the timer log of an event has
+func countLogged(t *testing.T, timerLogRepo *mock.MockTimerLogRepository, eventID uint, eventType domain.TimerEventType) int {
+	t.Helper()
+
+	entries, err := timerLogRepo.GetEventTimerLog(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetEventTimerLog: %v", err)
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func TestExpireTimersWithoutViewers(t *testing.T) {
+	ctx := context.Background()
+	timerRepo := mock.NewMockTimerRepository()
+	timerLogRepo := mock.NewMockTimerLogRepository()
+	h := NewTimerHandler(timerRepo, mock.NewMockAgendaRepository(), timerLogRepo, pubsub.NewHub[domain.TimerEvent]())
+
+	expired := startTimerAgo(t, timerRepo, 1, "talk", time.Minute, 2*time.Minute)
+	running := startTimerAgo(t, timerRepo, 2, "talk", time.Hour, time.Minute)
+
+	h.expireTimers(ctx)
+
+	if got := countLogged(t, timerLogRepo, 1, domain.TimerEventExpire); got != 1 {
+		t.Fatalf("expire entries of the timer that ran out: got %d, want 1", got)
+	}
+	if got := countLogged(t, timerLogRepo, 2, domain.TimerEventExpire); got != 0 {
+		t.Fatalf("expire entries of the running timer: got %d, want 0", got)
+	}
+
+	// The expiry is saved, so it is only recorded once
+	stillRunning, err := timerRepo.GetRunningTimers(ctx)
+	if err != nil {
+		t.Fatalf("GetRunningTimers: %v", err)
+	}
+	if len(stillRunning) != 1 || stillRunning[0].ID != running.ID {
+		t.Fatalf("running timers after expiry: got %+v, want timer %d", stillRunning, running.ID)
+	}
+	saved, err := timerRepo.GetTimerByID(ctx, expired.ID)
+	if err != nil {
+		t.Fatalf("GetTimerByID: %v", err)
+	}
+	if saved.State != domain.TimerStateOvertime {
+		t.Fatalf("state of the timer that ran out: got %s, want %s", saved.State, domain.TimerStateOvertime)
+	}
+
+	h.expireTimers(ctx)
+	if got := countLogged(t, timerLogRepo, 1, domain.TimerEventExpire); got != 1 {
+		t.Fatalf("expire entries after checking again: got %d, want 1", got)
+	}
+}
+
+// newAutoAdvanceHandlers returns timer and agenda handlers sharing repositories and a hub, with
+// the agenda auto-advancing on expiry as RegisterHandlers sets it up. Event 1 has an agenda with
+// auto-advance whose first segment ran out a minute ago.
+func newAutoAdvanceHandlers(t *testing.T) (*TimerHandler, *mock.MockAgendaRepository, *mock.MockTimerRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	timerRepo := mock.NewMockTimerRepository()
+	timerLogRepo := mock.NewMockTimerLogRepository()
+	agendaRepo := mock.NewMockAgendaRepository()
+	timerHub := pubsub.NewHub[domain.TimerEvent]()
+
+	_, err := agendaRepo.SaveAgenda(ctx, domain.Agenda{
+		EventID: 1,
+		Segments: []domain.AgendaSegment{
+			{Name: "talk", Duration: time.Minute},
+			{Name: "Q&A", Duration: 10 * time.Minute},
+		},
+		AutoAdvance: true,
+	})
+	if err != nil {
+		t.Fatalf("SaveAgenda: %v", err)
+	}
+	startTimerAgo(t, timerRepo, 1, "talk", time.Minute, 2*time.Minute)
+
+	timerHandler := NewTimerHandler(timerRepo, agendaRepo, timerLogRepo, timerHub)
+	agendaHandler := NewAgendaHandler(agendaRepo, timerRepo, timerLogRepo, timerHub)
+	timerHandler.OnExpire(agendaHandler.AutoAdvance)
+
+	return timerHandler, agendaRepo, timerRepo
+}
+
+// assertAdvanced fails unless the agenda of event 1 moved to its second segment and started its timer
+func assertAdvanced(t *testing.T, agendaRepo *mock.MockAgendaRepository, timerRepo *mock.MockTimerRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	agenda, err := agendaRepo.GetAgenda(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAgenda: %v", err)
+	}
+	if agenda.CurrentIndex != 1 {
+		t.Fatalf("current segment after the timer ran out: got %d, want 1", agenda.CurrentIndex)
+	}
+
+	next, err := timerRepo.GetEventTimer(ctx, 1, "Q&A")
+	if err != nil {
+		t.Fatalf("GetEventTimer: %v", err)
+	}
+	if next.State != domain.TimerStateRunning {
+		t.Fatalf("state of the next segment's timer: got %s, want %s", next.State, domain.TimerStateRunning)
+	}
+}
+
+func TestAutoAdvanceWithoutViewers(t *testing.T) {
+	timerHandler, agendaRepo, timerRepo := newAutoAdvanceHandlers(t)
+
+	timerHandler.expireTimers(context.Background())
+
+	assertAdvanced(t, agendaRepo, timerRepo)
+}
+
+func TestAutoAdvanceWithFullSubscriberBuffer(t *testing.T) {
+	ctx := context.Background()
+	timerHandler, agendaRepo, timerRepo := newAutoAdvanceHandlers(t)
+
+	// A subscriber that stopped reading, like a stalled event stream, misses the expire event
+	events, unsubscribe := timerHandler.timerHub.Subscribe()
+	defer unsubscribe()
+	for len(events) < cap(events) {
+		timerHandler.timerHub.Publish(domain.TimerEvent{Type: domain.TimerEventTick})
+	}
+
+	timerHandler.expireTimers(ctx)
+
+	assertAdvanced(t, agendaRepo, timerRepo)
+}
+
+func TestTimerPageDoesNotCreateTimers(t *testing.T) {
+	ctx := context.Background()
+	timerRepo := mock.NewMockTimerRepository()
+	agendaRepo := mock.NewMockAgendaRepository()
+	h := NewTimerHandler(timerRepo, agendaRepo, mock.NewMockTimerLogRepository(), pubsub.NewHub[domain.TimerEvent]())
+	e := echo.New()
+	h.RegisterRoutes(e)
+
+	_, err := agendaRepo.SaveAgenda(ctx, domain.Agenda{
+		EventID:  2,
+		Segments: []domain.AgendaSegment{{Name: "Demo", Duration: 10 * time.Minute}},
+	})
+	if err != nil {
+		t.Fatalf("SaveAgenda: %v", err)
+	}
+
+	for _, eventID := range []uint{1, 2} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timer?event="+strconv.FormatUint(uint64(eventID), 10), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("event %d: got status %d, want %d", eventID, rec.Code, http.StatusOK)
+		}
+		if !strings.Contains(rec.Body.String(), "Create Timer") {
+			t.Fatalf("event %d: page doesn't offer to create a timer", eventID)
+		}
+
+		timers, err := timerRepo.GetEventTimers(ctx, eventID)
+		if err != nil {
+			t.Fatalf("GetEventTimers: %v", err)
+		}
+		if len(timers) != 0 {
+			t.Fatalf("event %d: viewing the timer page created %d timers", eventID, len(timers))
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Decode reads the events of an iCalendar file. Times without a timezone, and times in
+// a timezone that isn't in the IANA database, are read in the floating location.
+// Properties the app doesn't use, and components other than VEVENT, are skipped.
+func Decode(r io.Reader, floating *time.Location) (Calendar, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	var (
+		calendar   Calendar
+		components []string
+		event      Event
+		inCalendar bool
+	)
+	for number, line := range lines {
+		if line == "" {
+			continue
+		}
+		property, err := parseProperty(line)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("line %d: %w", number+1, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			component := strings.ToUpper(property.Value)
+			if component == "VCALENDAR" {
+				inCalendar = true
+			} else if component == "VEVENT" {
+				event = Event{}
+			}
+			components = append(components, component)
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(property.Value) {
+				return Calendar{}, fmt.Errorf("line %d: unexpected END:%s", number+1, property.Value)
+			}
+			if components[len(components)-1] == "VEVENT" {
+				calendar.Events = append(calendar.Events, event)
+			}
+			components = components[:len(components)-1]
+			continue
+		}
+
+		if len(components) == 0 {
+			return Calendar{}, fmt.Errorf("line %d: %s outside of a component", number+1, property.Name)
+		}
+		switch components[len(components)-1] {
+		case "VCALENDAR":
+			if property.Name == "X-WR-CALNAME" {
+				calendar.Name = unescapeText(property.Value)
+			}
+		case "VEVENT":
+			if err := event.set(property, floating); err != nil {
+				return Calendar{}, fmt.Errorf("line %d: %w", number+1, err)
+			}
+		}
+	}
+
+	if !inCalendar {
+		return Calendar{}, fmt.Errorf("not an iCalendar file: no VCALENDAR")
+	}
+	if len(components) > 0 {
+		return Calendar{}, fmt.Errorf("unexpected end of file inside %s", components[len(components)-1])
+	}
+
+	return calendar, nil
+}
+
+// set reads a property of a VEVENT into the event
+func (e *Event) set(property property, floating *time.Location) error {
+	var err error
+	switch property.Name {
+	case "UID":
+		e.UID = unescapeText(property.Value)
+	case "SEQUENCE":
+		_, err = fmt.Sscan(property.Value, &e.Sequence)
+	case "LAST-MODIFIED":
+		e.Modified, err = parseDateTime(property, floating)
+	case "DTSTART":
+		e.Start, err = parseDateTime(property, floating)
+		e.TimeZone = ""
+		if e.Start.Location() != floating && e.Start.Location() != time.UTC {
+			e.TimeZone = e.Start.Location().String()
+		}
+	case "DTEND":
+		e.End, err = parseDateTime(property, floating)
+	case "SUMMARY":
+		e.Summary = unescapeText(property.Value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(property.Value)
+	case "URL":
+		e.URL = property.Value
+	case "ORGANIZER":
+		e.Organizer = unescapeText(property.Params["CN"])
+	case "STATUS":
+		e.Status = strings.ToUpper(property.Value)
+	case "RRULE":
+		e.RecurrenceRule = property.Value
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", property.Name, err)
+	}
+	return nil
+}
+
+// property is a content line split into its name, parameters and value
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseProperty splits a content line like DTSTART;TZID=Europe/Berlin:20250101T180000.
+// Parameter values may be quoted to contain colons and semicolons.
+func parseProperty(line string) (property, error) {
+	var (
+		parts   []string
+		start   int
+		quoted  bool
+		valueAt = -1
+	)
+	for i := 0; i < len(line) && valueAt < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				parts = append(parts, line[start:i])
+				start = i + 1
+			}
+		case ':':
+			if !quoted {
+				parts = append(parts, line[start:i])
+				valueAt = i + 1
+			}
+		}
+	}
+	if valueAt < 0 || parts[0] == "" {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	p := property{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string),
+		Value:  line[valueAt:],
+	}
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		p.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+// parseDateTime parses a DATE-TIME in UTC, in the timezone given by the TZID parameter
+// or floating, or a DATE, which is read as midnight
+func parseDateTime(p property, floating *time.Location) (time.Time, error) {
+	location := floating
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			location = loaded
+		}
+	}
+
+	switch {
+	case p.Params["VALUE"] == "DATE" || len(p.Value) == len("20060102"):
+		return time.ParseInLocation("20060102", p.Value, location)
+	case strings.HasSuffix(p.Value, "Z"):
+		return time.Parse(dateTimeFormat, p.Value)
+	default:
+		return time.ParseInLocation("20060102T150405", p.Value, location)
+	}
+}
+
+// unescapeText reverses escapeText
+func unescapeText(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+			switch text[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(text[i])
+			}
+			continue
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// unfoldLines reads the content lines of r, joining folded continuation lines.
+// Lines may end with CRLF as the standard requires or with LF only.
+func unfoldLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,125 @@
+// Package ical reads and writes iCalendar (RFC 5545) calendars with the few properties the app needs
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProductID identifies the app as the producer of its calendars
+const ProductID = "-//go-go-golems//AI in Action//EN"
+
+// maxLineLength is the number of octets after which content lines are folded
+const maxLineLength = 75
+
+// dateTimeFormat is the UTC form of an iCalendar DATE-TIME value
+const dateTimeFormat = "20060102T150405Z"
+
+// Calendar is a VCALENDAR holding events
+type Calendar struct {
+	// Name is shown by calendar apps subscribing to the calendar
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT. Calendar apps recognize an event they already know by its UID
+// and replace it when Sequence grows or Modified, its LAST-MODIFIED, is later.
+// The fields after Status are only read by Decode and not written by Encode.
+type Event struct {
+	UID         string
+	Sequence    int
+	Modified    time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+	// Status is CONFIRMED, TENTATIVE or CANCELLED, empty if not given
+	Status string
+
+	// TimeZone is the IANA name of the timezone Start was given in, empty for UTC and floating times
+	TimeZone string
+	// Organizer is the common name of the organizer
+	Organizer string
+	// RecurrenceRule is the RRULE of a recurring event, empty for single events
+	RecurrenceRule string
+}
+
+// Encode writes the calendar to w. stamp is when the calendar is generated, which every
+// event carries as its DTSTAMP; when an event was last changed is given by its Modified.
+func Encode(w io.Writer, calendar Calendar, stamp time.Time) error {
+	b := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeLine(b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", ProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		line("X-WR-CALNAME", escapeText(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(event.UID))
+		line("SEQUENCE", fmt.Sprint(event.Sequence))
+		line("DTSTAMP", formatDateTime(stamp))
+		if !event.Modified.IsZero() {
+			line("LAST-MODIFIED", formatDateTime(event.Modified))
+		}
+		line("DTSTART", formatDateTime(event.Start))
+		line("DTEND", formatDateTime(event.End))
+		line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		if event.Status != "" {
+			line("STATUS", event.Status)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+// formatDateTime formats a time as a DATE-TIME in UTC
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escapeText escapes a TEXT value, turning line breaks into \n
+func escapeText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// writeLine writes a content line ended by CRLF, folding it into continuation lines
+// that start with a space so no line is longer than 75 octets. Lines are only folded
+// between characters, never inside a multi-byte UTF-8 sequence.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose an octet to the leading space
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,114 @@
+package ical
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	start := time.Date(2025, 3, 6, 18, 0, 0, 0, time.UTC)
+	calendar := Calendar{
+		Name: "AI in Action, Berlin",
+		Events: []Event{
+			{
+				UID:      "event-1@ai-in-action",
+				Sequence: 3,
+				Modified: start.Add(-48 * time.Hour),
+				Start:    start,
+				End:      start.Add(time.Hour),
+				Summary:  "Prompting; tips, tricks\\and traps",
+				Description: "A line with a comma, a semicolon; and a backslash \\.\n" +
+					"A second line that is long enough to be folded across several content lines, " +
+					"with umlauts like äöü and an emoji 🤖 right where the fold might land.",
+				URL:    "https://example.com/events/1",
+				Status: "CONFIRMED",
+			},
+			{
+				UID:      "event-2@ai-in-action",
+				Modified: start,
+				Start:    start.AddDate(0, 0, 7),
+				End:      start.AddDate(0, 0, 7).Add(30 * time.Minute),
+				Summary:  "Open slot",
+				Status:   "TENTATIVE",
+			},
+		},
+	}
+
+	stamp := start.Add(-time.Hour)
+	var buf bytes.Buffer
+	if err := Encode(&buf, calendar, stamp); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	// Events are stamped with the time the calendar was generated and keep their own last change
+	encoded := buf.String()
+	for _, want := range []string{"DTSTAMP:20250306T170000Z\r\n", "LAST-MODIFIED:20250304T180000Z\r\n", "LAST-MODIFIED:20250306T180000Z\r\n"} {
+		if !strings.Contains(encoded, want) {
+			t.Errorf("encoded calendar lacks %q", want)
+		}
+	}
+	if count := strings.Count(encoded, "DTSTAMP:"); count != len(calendar.Events) {
+		t.Errorf("encoded calendar has %d DTSTAMP lines, want %d", count, len(calendar.Events))
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line is %d octets long, want at most %d: %q", len(line), maxLineLength, line)
+		}
+	}
+
+	decoded, err := Decode(&buf, time.UTC)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, calendar) {
+		t.Errorf("Decode(Encode(calendar)) = %+v, want %+v", decoded, calendar)
+	}
+}
+
+func TestDecodeTimeZones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:zoned",
+		"DTSTART;TZID=Europe/Berlin:20250306T190000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20250306T190000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	calendar, err := Decode(strings.NewReader(input), time.UTC)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(calendar.Events) != 2 {
+		t.Fatalf("Decode returned %d events, want 2", len(calendar.Events))
+	}
+
+	zoned := calendar.Events[0]
+	if want := time.Date(2025, 3, 6, 19, 0, 0, 0, berlin); !zoned.Start.Equal(want) {
+		t.Errorf("zoned Start = %s, want %s", zoned.Start, want)
+	}
+	if zoned.TimeZone != "Europe/Berlin" {
+		t.Errorf("zoned TimeZone = %q, want %q", zoned.TimeZone, "Europe/Berlin")
+	}
+
+	floating := calendar.Events[1]
+	if want := time.Date(2025, 3, 6, 19, 0, 0, 0, time.UTC); !floating.Start.Equal(want) {
+		t.Errorf("floating Start = %s, want %s", floating.Start, want)
+	}
+	if floating.TimeZone != "" {
+		t.Errorf("floating TimeZone = %q, want empty", floating.TimeZone)
+	}
+}
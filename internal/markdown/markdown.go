@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// converter turns GitHub flavored Markdown into HTML. Raw HTML in the source is
+// escaped by goldmark and the output is sanitized again by policy.
+var converter = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+)
+
+// policy allows the HTML Markdown produces, keeping the language class of fenced code blocks
+var policy = newPolicy()
+
+// newPolicy builds the sanitization policy for rendered Markdown
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// Render converts Markdown to sanitized HTML that is safe to embed in a page.
+// Markdown that can't be converted is returned as plain text with all HTML removed.
+func Render(source string) string {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		return bluemonday.StrictPolicy().Sanitize(source)
+	}
+
+	return string(policy.SanitizeBytes(buf.Bytes()))
+}
@@ -0,0 +1,79 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "script tag",
+			source:     "Hello <script>alert(1)</script> world",
+			contains:   []string{"Hello", "world"},
+			notContain: []string{"<script", "</script"},
+		},
+		{
+			name:       "inline event handler",
+			source:     `<div onclick="alert(1)">click me</div>`,
+			notContain: []string{"onclick", "alert(1)"},
+		},
+		{
+			name:       "javascript link",
+			source:     "[click me](javascript:alert(1))",
+			contains:   []string{"click me"},
+			notContain: []string{"javascript:", "href"},
+		},
+		{
+			name:       "raw iframe",
+			source:     `<iframe src="https://example.com/evil"></iframe>`,
+			notContain: []string{"<iframe", "example.com/evil"},
+		},
+		{
+			name:       "raw image with onerror",
+			source:     `<img src="x" onerror="alert(1)">`,
+			notContain: []string{"<img", "onerror", "alert(1)"},
+		},
+		{
+			name:     "fenced code block",
+			source:   "```go\nfmt.Println(\"hi\")\n```",
+			contains: []string{`<code class="language-go">`, "fmt.Println"},
+		},
+		{
+			name:       "code block with a made up class",
+			source:     "```go onclick=alert(1)\nx\n```",
+			notContain: []string{"onclick"},
+		},
+		{
+			name:     "link",
+			source:   "[the slides](https://example.com/slides)",
+			contains: []string{`href="https://example.com/slides"`, `rel="nofollow noopener"`, `target="_blank"`, "the slides"},
+		},
+		{
+			name:     "autolink",
+			source:   "See https://example.com/repo",
+			contains: []string{`href="https://example.com/repo"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.source)
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, want)
+				}
+			}
+			for _, unwanted := range tt.notContain {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Render(%q) = %q, want it not to contain %q", tt.source, got, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package pubsub
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is the number of messages buffered per subscriber
+const subscriberBuffer = 16
+
+// Hub is an in-process publish/subscribe hub that fans messages out to all subscribers
+type Hub[T any] struct {
+	subscribers map[chan T]struct{}
+	mu          sync.RWMutex
+}
+
+// NewHub creates a new hub without subscribers
+func NewHub[T any]() *Hub[T] {
+	return &Hub[T]{
+		subscribers: make(map[chan T]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its message channel
+// together with a function that unsubscribes and closes the channel
+func (h *Hub[T]) Subscribe() (<-chan T, func()) {
+	ch := make(chan T, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish sends a message to all subscribers.
+// Subscribers that are not keeping up miss the message instead of blocking the publisher.
+func (h *Hub[T]) Publish(msg T) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (h *Hub[T]) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscribers)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+// ErrNotFound is returned when a requested entity does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrAlreadyExists is returned when creating an entity that conflicts with an existing one
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrConflict is returned when an update loses a race against a concurrent update
+var ErrConflict = errors.New("conflicting update")
@@ -7,30 +7,162 @@ import (
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 )
 
-// EventRepository defines the interface for event data operations
+// EventCursor is a position in the past events, which are ordered by date and then ID, newest first
+type EventCursor struct {
+	Date time.Time
+	ID   uint
+}
+
+// Repositories holds one repository of each kind, which the handlers are built on
+type Repositories struct {
+	Events    EventRepository
+	Speakers  SpeakerRepository
+	Resources ResourceRepository
+	Series    SeriesRepository
+	Timers    TimerRepository
+	TimerLogs TimerLogRepository
+	Agendas   AgendaRepository
+	Notes     NoteRepository
+	Questions QuestionRepository
+}
+
+// EventRepository defines the interface for event data operations.
+// Events are returned with their Speakers. AddEvent, UpdateEvent and ClaimSlot link an event to
+// the speakers of its SpeakerList, matched by name ignoring case and added if they are new, and
+// set its speaker line to their names.
 type EventRepository interface {
+	// GetUpcomingEvents returns the events that aren't over yet at the repository's clock, soonest first,
+	// including open slots
 	GetUpcomingEvents(ctx context.Context) ([]domain.Event, error)
+	// GetPastEvents returns the events that are over at the repository's clock, newest first.
+	// Open slots nobody claimed are left out.
 	GetPastEvents(ctx context.Context) ([]domain.Event, error)
+	// SearchPastEvents returns up to limit past events newest first, starting after the cursor when
+	// one is given. A non-empty search only returns events whose title, speaker or description match it.
+	// Like GetPastEvents it leaves out open slots.
+	SearchPastEvents(ctx context.Context, search string, after *EventCursor, limit int) ([]domain.Event, error)
+	// GetEvent returns an event by ID, or ErrNotFound
+	GetEvent(ctx context.Context, id uint) (domain.Event, error)
+	// GetEventByUID returns the event imported with an iCalendar UID, or ErrNotFound
+	GetEventByUID(ctx context.Context, uid string) (domain.Event, error)
 	AddEvent(ctx context.Context, event domain.Event) (domain.Event, error)
+	// UpdateEvent saves the details of an event and counts up its Sequence. The UID of an event never changes.
 	UpdateEvent(ctx context.Context, event domain.Event) (bool, error)
+	// DeleteEvent removes an event from the timeline. Deleted events are kept for the record
+	// but no longer returned.
+	DeleteEvent(ctx context.Context, id uint) (bool, error)
+	// AddSeriesSlots adds the open slots of a series that don't exist yet and returns how many it added.
+	// A slot exists if an event was generated for its series occurrence before, even if that event
+	// was moved or deleted since, so deleting a slot cancels the session.
+	AddSeriesSlots(ctx context.Context, slots []domain.Event) (int, error)
+	// ClaimSlot turns an open slot into a talk with the title, speaker and description of event.
+	// It returns false if the slot doesn't exist and ErrConflict if it isn't open anymore.
+	ClaimSlot(ctx context.Context, event domain.Event) (bool, error)
+	// DeleteOpenSlots removes the open slots of a series that haven't started yet and returns how many
+	DeleteOpenSlots(ctx context.Context, seriesID uint) (int, error)
+	// GetSpeakerEvents returns the events a speaker gives or gave, soonest first
+	GetSpeakerEvents(ctx context.Context, speakerID uint) ([]domain.Event, error)
+}
+
+// SpeakerRepository defines the interface for speaker profiles.
+// Speakers are added when events are linked to them, see EventRepository.
+type SpeakerRepository interface {
+	// GetAllSpeakers returns all speakers ordered by name
+	GetAllSpeakers(ctx context.Context) ([]domain.Speaker, error)
+	// GetSpeaker returns a speaker by ID, or ErrNotFound
+	GetSpeaker(ctx context.Context, id uint) (domain.Speaker, error)
+	// SearchSpeakers returns up to limit speakers whose name contains search, ignoring case, ordered by name
+	SearchSpeakers(ctx context.Context, search string, limit int) ([]domain.Speaker, error)
+	// UpdateSpeaker updates the profile of a speaker and the speaker line of their events.
+	// It returns ErrConflict if another speaker has the same name.
+	UpdateSpeaker(ctx context.Context, speaker domain.Speaker) (bool, error)
+}
+
+// ResourceRepository defines the interface for the resources attached to events
+type ResourceRepository interface {
+	// GetEventResources returns the resources of an event in the order they were added
+	GetEventResources(ctx context.Context, eventID uint) ([]domain.Resource, error)
+	// GetResource returns a resource by ID, or ErrNotFound
+	GetResource(ctx context.Context, id uint) (domain.Resource, error)
+	AddResource(ctx context.Context, resource domain.Resource) (domain.Resource, error)
+	DeleteResource(ctx context.Context, id uint) (bool, error)
+}
+
+// SeriesRepository defines the interface for recurring session series
+type SeriesRepository interface {
+	GetAllSeries(ctx context.Context) ([]domain.Series, error)
+	// GetSeries returns a series by ID, or ErrNotFound
+	GetSeries(ctx context.Context, id uint) (domain.Series, error)
+	AddSeries(ctx context.Context, series domain.Series) (domain.Series, error)
+	// DeleteSeries ends a series so it generates no more slots
+	DeleteSeries(ctx context.Context, id uint) (bool, error)
 }
 
-// TimerRepository defines the interface for timer data operations
+// TimerRepository defines the interface for timer data operations.
+// GetTimer and ResetTimer operate on the default timer that isn't tied to an event.
+// Reads return timers as of now without writing. UpdateTimer only succeeds if the
+// timer's Version still matches the stored one and returns ErrConflict otherwise.
 type TimerRepository interface {
 	GetTimer(ctx context.Context) (domain.Timer, error)
+	GetTimerByID(ctx context.Context, id uint) (domain.Timer, error)
 	UpdateTimer(ctx context.Context, timer domain.Timer) (bool, error)
 	ResetTimer(ctx context.Context, duration time.Duration) (domain.Timer, error)
+	CreateEventTimer(ctx context.Context, eventID uint, segment string, duration time.Duration) (domain.Timer, error)
+	GetEventTimers(ctx context.Context, eventID uint) ([]domain.Timer, error)
+	GetEventTimer(ctx context.Context, eventID uint, segment string) (domain.Timer, error)
+	// GetRunningTimers returns the timers saved as running down with time left. A returned
+	// timer in overtime has run out since it was saved, which hasn't been recorded yet.
+	GetRunningTimers(ctx context.Context) ([]domain.Timer, error)
+}
+
+// TimerLogRepository defines the interface for the timer event log.
+// Entries are only ever added, so the history survives timers being reset.
+type TimerLogRepository interface {
+	AddTimerLogEntry(ctx context.Context, entry domain.TimerLogEntry) (domain.TimerLogEntry, error)
+	GetEventTimerLog(ctx context.Context, eventID uint) ([]domain.TimerLogEntry, error)
+}
+
+// AgendaRepository defines the interface for event agenda data operations.
+// SetCurrentSegment only moves the agenda if it is still at segment from and returns ErrConflict otherwise.
+type AgendaRepository interface {
+	GetAgenda(ctx context.Context, eventID uint) (domain.Agenda, error)
+	SaveAgenda(ctx context.Context, agenda domain.Agenda) (domain.Agenda, error)
+	SetCurrentSegment(ctx context.Context, eventID uint, from int, to int) (bool, error)
 }
 
-// NoteRepository defines the interface for note data operations
+// NoteRepository defines the interface for note data operations.
+// Pages are numbered per event and speaker; SaveNote stores a note under its EventID and Speaker.
 type NoteRepository interface {
-	GetNote(ctx context.Context, pageNumber int) (domain.Note, error)
+	GetNote(ctx context.Context, eventID uint, speaker string, pageNumber int) (domain.Note, error)
 	SaveNote(ctx context.Context, note domain.Note) (bool, error)
 }
 
-// QuestionRepository defines the interface for question data operations
+// QuestionRepository defines the interface for question data operations.
+// Lists include questions in every moderation state; callers filter out hidden and merged ones.
 type QuestionRepository interface {
-	GetQuestions(ctx context.Context) ([]domain.Question, error)
+	// GetQuestions returns the questions of an event, newest first
+	GetQuestions(ctx context.Context, eventID uint) ([]domain.Question, error)
+	// GetQuestion returns a question by ID, or ErrNotFound
+	GetQuestion(ctx context.Context, id uint) (domain.Question, error)
 	AddQuestion(ctx context.Context, question domain.Question) (domain.Question, error)
+	// MarkAsAnswered marks a pending or pinned question as answered
 	MarkAsAnswered(ctx context.Context, id uint) (bool, error)
+	// UpdateQuestion saves the name and content of a question
+	UpdateQuestion(ctx context.Context, question domain.Question) (bool, error)
+	// SetQuestionState moves a question from one moderation state to another. It returns false if
+	// the question doesn't exist and ErrConflict if it is no longer in the from state.
+	SetQuestionState(ctx context.Context, id uint, from domain.QuestionState, to domain.QuestionState) (bool, error)
+	// PinQuestion pins a question as the one being answered now.
+	// The question pinned before it in the same event is marked answered.
+	PinQuestion(ctx context.Context, id uint) (bool, error)
+	// MergeQuestions folds the source question into the target question. The votes of the source
+	// move to the target, counting voters who voted for both only once.
+	MergeQuestions(ctx context.Context, sourceID uint, targetID uint) (bool, error)
+	// GetRankedQuestions returns the questions of an event with the most votes first, then the oldest first
+	GetRankedQuestions(ctx context.Context, eventID uint) ([]domain.Question, error)
+	// Upvote adds the vote of a voter to a question. It returns false if the question doesn't exist
+	// and ErrAlreadyExists if the voter already voted for it.
+	Upvote(ctx context.Context, questionID uint, voterID string) (bool, error)
+	// GetVotedQuestionIDs returns the IDs of the questions a voter voted for
+	GetVotedQuestionIDs(ctx context.Context, voterID string) ([]uint, error)
 }
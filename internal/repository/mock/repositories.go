@@ -2,9 +2,13 @@ package mock
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
 )
@@ -12,27 +16,48 @@ import (
 // MockEventRepository implements the EventRepository interface with in-memory storage
 type MockEventRepository struct {
 	events []domain.Event
+	clock  clock.Clock
 	mu     sync.RWMutex
 	nextID uint
+	// generated holds the series occurrences slots were added for, including deleted ones
+	generated map[slotKey]bool
+	// speakers are shared with the MockSpeakerRepository made for this repository
+	speakers      []domain.Speaker
+	nextSpeakerID uint
+}
+
+// slotKey identifies the slot of a series occurrence
+type slotKey struct {
+	seriesID   uint
+	occurrence int64
 }
 
 var _ repository.EventRepository = &MockEventRepository{}
 
-// NewMockEventRepository creates a new mock event repository with sample data
-func NewMockEventRepository() *MockEventRepository {
+// NewMockEventRepository creates a new mock event repository with sample data.
+// The sample events are placed around the clock's current time, two ahead and two behind,
+// and scheduled in timeZone.
+func NewMockEventRepository(clock clock.Clock, timeZone *time.Location) *MockEventRepository {
 	repo := &MockEventRepository{
-		events: make([]domain.Event, 0),
-		nextID: 1,
+		events:        make([]domain.Event, 0),
+		clock:         clock,
+		nextID:        1,
+		nextSpeakerID: 1,
 	}
 
+	// Sample events are at 18:00, a week apart. Adding days keeps them at 18:00 across DST changes.
+	now := clock.Now().In(timeZone)
+	evening := time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, timeZone)
+
 	// Add sample upcoming events
 	repo.events = append(repo.events, domain.Event{
 		ID:          repo.nextID,
 		Title:       "Generative AI for Scientific Discovery",
-		Speaker:     "Dr. Alex Chen",
+		Speaker:     "Dr. Alex Chen & Sam Rodriguez",
 		Description: "Exploring how generative AI models can accelerate scientific discovery in various domains.",
-		Date:        time.Date(2025, 3, 6, 18, 0, 0, 0, time.Local),
-		IsUpcoming:  true,
+		Date:        evening.AddDate(0, 0, 7),
+		TimeZone:    timeZone.String(),
+		UpdatedAt:   now,
 	})
 	repo.nextID++
 
@@ -41,8 +66,9 @@ func NewMockEventRepository() *MockEventRepository {
 		Title:       "Ethical Considerations in AI Development",
 		Speaker:     "Prof. Maya Johnson",
 		Description: "Discussing the ethical frameworks necessary for responsible AI development.",
-		Date:        time.Date(2025, 3, 13, 18, 0, 0, 0, time.Local),
-		IsUpcoming:  true,
+		Date:        evening.AddDate(0, 0, 14),
+		TimeZone:    timeZone.String(),
+		UpdatedAt:   now,
 	})
 	repo.nextID++
 
@@ -52,8 +78,9 @@ func NewMockEventRepository() *MockEventRepository {
 		Title:       "Multimodal Learning in AI",
 		Speaker:     "Sam Rodriguez",
 		Description: "How combining different data modalities can enhance AI model capabilities.",
-		Date:        time.Date(2025, 2, 20, 18, 0, 0, 0, time.Local),
-		IsUpcoming:  false,
+		Date:        evening.AddDate(0, 0, -7),
+		TimeZone:    timeZone.String(),
+		UpdatedAt:   now,
 	})
 	repo.nextID++
 
@@ -62,15 +89,21 @@ func NewMockEventRepository() *MockEventRepository {
 		Title:       "Reinforcement Learning from Human Feedback",
 		Speaker:     "Dr. Jamie Park",
 		Description: "Deep dive into how RLHF is transforming the alignment of AI systems.",
-		Date:        time.Date(2025, 2, 13, 18, 0, 0, 0, time.Local),
-		IsUpcoming:  false,
+		Date:        evening.AddDate(0, 0, -14),
+		TimeZone:    timeZone.String(),
+		UpdatedAt:   now,
 	})
 	repo.nextID++
 
+	// Link the sample events to their speakers
+	for i, event := range repo.events {
+		repo.events[i] = repo.linkSpeakers(event)
+	}
+
 	return repo
 }
 
-// GetUpcomingEvents returns all upcoming events
+// GetUpcomingEvents returns the events that aren't over yet, soonest first
 func (m *MockEventRepository) GetUpcomingEvents(ctx context.Context) ([]domain.Event, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -80,16 +113,20 @@ func (m *MockEventRepository) GetUpcomingEvents(ctx context.Context) ([]domain.E
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	now := m.clock.Now()
 	upcomingEvents := make([]domain.Event, 0)
 	for _, event := range m.events {
-		if event.IsUpcoming {
+		if !event.IsOver(now) {
 			upcomingEvents = append(upcomingEvents, event)
 		}
 	}
+	sort.SliceStable(upcomingEvents, func(i, j int) bool {
+		return upcomingEvents[i].Date.Before(upcomingEvents[j].Date)
+	})
 	return upcomingEvents, nil
 }
 
-// GetPastEvents returns all past events
+// GetPastEvents returns the events that are over, newest first, leaving out unclaimed slots
 func (m *MockEventRepository) GetPastEvents(ctx context.Context) ([]domain.Event, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -99,13 +136,100 @@ func (m *MockEventRepository) GetPastEvents(ctx context.Context) ([]domain.Event
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	return m.pastEvents(), nil
+}
+
+// SearchPastEvents returns a page of past events newest first, matching search when it isn't empty.
+// Every word of the search has to appear in the title, speaker or description, ignoring case.
+func (m *MockEventRepository) SearchPastEvents(ctx context.Context, search string, after *repository.EventCursor, limit int) ([]domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	words := strings.Fields(strings.ToLower(search))
+	events := make([]domain.Event, 0)
+	for _, event := range m.pastEvents() {
+		if after != nil && !event.Date.Before(after.Date) && !(event.Date.Equal(after.Date) && event.ID < after.ID) {
+			continue
+		}
+
+		text := strings.ToLower(event.Title + " " + event.Speaker + " " + event.Description)
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		events = append(events, event)
+		if len(events) == limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+// pastEvents returns the events that are over, newest first, leaving out unclaimed slots.
+// The caller must hold the lock.
+func (m *MockEventRepository) pastEvents() []domain.Event {
+	now := m.clock.Now()
 	pastEvents := make([]domain.Event, 0)
 	for _, event := range m.events {
-		if !event.IsUpcoming {
+		if event.IsOver(now) && !event.Open {
 			pastEvents = append(pastEvents, event)
 		}
 	}
-	return pastEvents, nil
+	sort.SliceStable(pastEvents, func(i, j int) bool {
+		if !pastEvents[i].Date.Equal(pastEvents[j].Date) {
+			return pastEvents[i].Date.After(pastEvents[j].Date)
+		}
+		return pastEvents[i].ID > pastEvents[j].ID
+	})
+	return pastEvents
+}
+
+// GetEvent returns an event by ID
+func (m *MockEventRepository) GetEvent(ctx context.Context, id uint) (domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Event{}, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, event := range m.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return domain.Event{}, fmt.Errorf("event %d: %w", id, repository.ErrNotFound)
+}
+
+// GetEventByUID returns the event imported with an iCalendar UID
+func (m *MockEventRepository) GetEventByUID(ctx context.Context, uid string) (domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Event{}, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, event := range m.events {
+		if uid != "" && event.UID == uid {
+			return event, nil
+		}
+	}
+	return domain.Event{}, fmt.Errorf("event %q: %w", uid, repository.ErrNotFound)
 }
 
 // AddEvent adds a new event and returns it with an ID
@@ -118,13 +242,16 @@ func (m *MockEventRepository) AddEvent(ctx context.Context, event domain.Event)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	event = m.linkSpeakers(event)
 	event.ID = m.nextID
+	event.Sequence = 0
+	event.UpdatedAt = m.clock.Now()
 	m.nextID++
 	m.events = append(m.events, event)
 	return event, nil
 }
 
-// UpdateEvent updates an existing event
+// UpdateEvent updates an existing event and counts up its sequence
 func (m *MockEventRepository) UpdateEvent(ctx context.Context, event domain.Event) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -136,6 +263,13 @@ func (m *MockEventRepository) UpdateEvent(ctx context.Context, event domain.Even
 
 	for i, e := range m.events {
 		if e.ID == event.ID {
+			event = m.linkSpeakers(event)
+			event.UID = e.UID
+			event.SeriesID = e.SeriesID
+			event.Occurrence = e.Occurrence
+			event.Open = e.Open
+			event.Sequence = e.Sequence + 1
+			event.UpdatedAt = m.clock.Now()
 			m.events[i] = event
 			return true, nil
 		}
@@ -143,10 +277,171 @@ func (m *MockEventRepository) UpdateEvent(ctx context.Context, event domain.Even
 	return false, nil
 }
 
+// DeleteEvent removes an event
+func (m *MockEventRepository) DeleteEvent(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.events {
+		if e.ID == id {
+			m.events = append(m.events[:i], m.events[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddSeriesSlots adds the open slots of a series that weren't generated before
+func (m *MockEventRepository) AddSeriesSlots(ctx context.Context, slots []domain.Event) (int, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.generated == nil {
+		m.generated = make(map[slotKey]bool)
+	}
+
+	added := 0
+	for _, slot := range slots {
+		key := slotKey{seriesID: slot.SeriesID, occurrence: slot.Occurrence.UnixNano()}
+		if m.generated[key] {
+			continue
+		}
+		m.generated[key] = true
+
+		slot.ID = m.nextID
+		slot.Sequence = 0
+		slot.UpdatedAt = m.clock.Now()
+		m.nextID++
+		m.events = append(m.events, slot)
+		added++
+	}
+	return added, nil
+}
+
+// ClaimSlot turns an open slot into a talk, as long as nobody claimed it first
+func (m *MockEventRepository) ClaimSlot(ctx context.Context, event domain.Event) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.events {
+		if e.ID != event.ID {
+			continue
+		}
+		if !e.Open {
+			return false, fmt.Errorf("event %d isn't an open slot: %w", event.ID, repository.ErrConflict)
+		}
+		event = m.linkSpeakers(event)
+		e.Title = event.Title
+		e.Speaker = event.Speaker
+		e.Speakers = event.Speakers
+		e.Description = event.Description
+		e.Open = false
+		e.Sequence++
+		e.UpdatedAt = m.clock.Now()
+		m.events[i] = e
+		return true, nil
+	}
+	return false, nil
+}
+
+// DeleteOpenSlots removes the open slots of a series that haven't started yet
+func (m *MockEventRepository) DeleteOpenSlots(ctx context.Context, seriesID uint) (int, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	events := make([]domain.Event, 0, len(m.events))
+	for _, e := range m.events {
+		if e.SeriesID == seriesID && e.Open && e.Date.After(now) {
+			continue
+		}
+		events = append(events, e)
+	}
+	deleted := len(m.events) - len(events)
+	m.events = events
+	return deleted, nil
+}
+
+// GetSpeakerEvents returns the events a speaker gives or gave, soonest first
+func (m *MockEventRepository) GetSpeakerEvents(ctx context.Context, speakerID uint) ([]domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	events := make([]domain.Event, 0)
+	for _, event := range m.events {
+		for _, speaker := range event.Speakers {
+			if speaker.ID == speakerID {
+				events = append(events, event)
+				break
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	return events, nil
+}
+
+// linkSpeakers sets the Speakers of event to the stored speakers of its SpeakerList, adding
+// the ones that don't exist yet, and its speaker line to their names. The caller must hold the lock.
+func (m *MockEventRepository) linkSpeakers(event domain.Event) domain.Event {
+	var speakers []domain.Speaker
+	for _, speaker := range event.SpeakerList() {
+		index := m.speakerIndex(speaker.Name, 0)
+		if index < 0 {
+			m.speakers = append(m.speakers, domain.Speaker{ID: m.nextSpeakerID, Name: speaker.Name})
+			m.nextSpeakerID++
+			index = len(m.speakers) - 1
+		}
+		speakers = append(speakers, m.speakers[index])
+	}
+
+	event.Speakers = speakers
+	event.Speaker = domain.SpeakerLine(speakers)
+	return event
+}
+
+// speakerIndex returns the index of the speaker named name ignoring case, other than the
+// speaker with ID except, or -1. The caller must hold the lock.
+func (m *MockEventRepository) speakerIndex(name string, except uint) int {
+	for i, speaker := range m.speakers {
+		if speaker.ID != except && strings.EqualFold(speaker.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
 // MockTimerRepository implements the TimerRepository interface with in-memory storage
 type MockTimerRepository struct {
-	timer domain.Timer
-	mu    sync.RWMutex
+	timers map[uint]domain.Timer
+	mu     sync.Mutex
+	nextID uint
 }
 
 var _ repository.TimerRepository = &MockTimerRepository{}
@@ -154,42 +449,43 @@ var _ repository.TimerRepository = &MockTimerRepository{}
 // NewMockTimerRepository creates a new mock timer repository
 func NewMockTimerRepository() *MockTimerRepository {
 	return &MockTimerRepository{
-		timer: domain.Timer{
-			ID:            1,
-			Duration:      15 * time.Minute,
-			RemainingTime: 15 * time.Minute,
-			IsRunning:     false,
-		},
+		timers: make(map[uint]domain.Timer),
+		nextID: 1,
 	}
 }
 
-// GetTimer returns the current timer
+// GetTimer returns the default timer
 func (m *MockTimerRepository) GetTimer(ctx context.Context) (domain.Timer, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return domain.Timer{}, ctx.Err()
 	}
 
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// If timer is running, calculate the remaining time
-	if m.timer.IsRunning {
-		elapsed := time.Since(m.timer.LastStartedAt)
-		if elapsed < m.timer.RemainingTime {
-			m.timer.RemainingTime = m.timer.RemainingTime - elapsed
-			m.timer.LastStartedAt = time.Now()
-		} else {
-			// Timer has expired
-			m.timer.RemainingTime = 0
-			m.timer.IsRunning = false
-		}
+	return m.timers[m.defaultTimerID()].At(time.Now()), nil
+}
+
+// GetTimerByID returns the timer with the given ID
+func (m *MockTimerRepository) GetTimerByID(ctx context.Context, id uint) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timer, exists := m.timers[id]
+	if !exists {
+		return domain.Timer{}, fmt.Errorf("timer %d: %w", id, repository.ErrNotFound)
 	}
 
-	return m.timer, nil
+	return timer.At(time.Now()), nil
 }
 
-// UpdateTimer updates the timer state
+// UpdateTimer updates the timer state if nobody else updated it since it was read
 func (m *MockTimerRepository) UpdateTimer(ctx context.Context, timer domain.Timer) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -199,11 +495,20 @@ func (m *MockTimerRepository) UpdateTimer(ctx context.Context, timer domain.Time
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.timer = timer
+	stored, exists := m.timers[timer.ID]
+	if !exists {
+		return false, nil
+	}
+	if stored.Version != timer.Version {
+		return false, fmt.Errorf("timer %d at version %d: %w", timer.ID, timer.Version, repository.ErrConflict)
+	}
+
+	timer.Version++
+	m.timers[timer.ID] = timer
 	return true, nil
 }
 
-// ResetTimer resets the timer with a new duration
+// ResetTimer resets the default timer with a new duration
 func (m *MockTimerRepository) ResetTimer(ctx context.Context, duration time.Duration) (domain.Timer, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -213,16 +518,138 @@ func (m *MockTimerRepository) ResetTimer(ctx context.Context, duration time.Dura
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.timer.Duration = duration
-	m.timer.RemainingTime = duration
-	m.timer.IsRunning = false
-	return m.timer, nil
+	id := m.defaultTimerID()
+	timer := m.timers[id]
+	timer.Reset(duration)
+	timer.Version++
+	m.timers[id] = timer
+	return timer, nil
+}
+
+// CreateEventTimer creates a timer for an event segment
+func (m *MockTimerRepository) CreateEventTimer(ctx context.Context, eventID uint, segment string, duration time.Duration) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.findTimer(eventID, segment); exists {
+		return domain.Timer{}, fmt.Errorf("timer for event %d segment %q: %w", eventID, segment, repository.ErrAlreadyExists)
+	}
+
+	timer := domain.NewTimer(duration)
+	timer.ID = m.nextID
+	timer.EventID = eventID
+	timer.Segment = segment
+	m.nextID++
+	m.timers[timer.ID] = timer
+	return timer, nil
+}
+
+// GetEventTimers returns all timers of an event ordered by creation
+func (m *MockTimerRepository) GetEventTimers(ctx context.Context, eventID uint) ([]domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	timers := make([]domain.Timer, 0)
+	for _, timer := range m.timers {
+		if timer.EventID == eventID {
+			timers = append(timers, timer.At(now))
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].ID < timers[j].ID
+	})
+	return timers, nil
+}
+
+// GetEventTimer returns the timer of an event segment
+func (m *MockTimerRepository) GetEventTimer(ctx context.Context, eventID uint, segment string) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, exists := m.findTimer(eventID, segment)
+	if !exists {
+		return domain.Timer{}, fmt.Errorf("timer for event %d segment %q: %w", eventID, segment, repository.ErrNotFound)
+	}
+
+	return m.timers[id].At(time.Now()), nil
+}
+
+// GetRunningTimers returns the timers saved as running down with time left, ordered by ID
+func (m *MockTimerRepository) GetRunningTimers(ctx context.Context) ([]domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	timers := make([]domain.Timer, 0)
+	for _, timer := range m.timers {
+		if timer.State == domain.TimerStateRunning {
+			timers = append(timers, timer.At(now))
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].ID < timers[j].ID
+	})
+	return timers, nil
+}
+
+// defaultTimerID returns the ID of the default timer, creating it if needed.
+// The caller must hold the lock.
+func (m *MockTimerRepository) defaultTimerID() uint {
+	if id, exists := m.findTimer(0, ""); exists {
+		return id
+	}
+
+	timer := domain.NewTimer(15 * time.Minute)
+	timer.ID = m.nextID
+	m.nextID++
+	m.timers[timer.ID] = timer
+	return timer.ID
+}
+
+// findTimer looks up the ID of an event segment timer.
+// The caller must hold the lock.
+func (m *MockTimerRepository) findTimer(eventID uint, segment string) (uint, bool) {
+	for id, timer := range m.timers {
+		if timer.EventID == eventID && timer.Segment == segment {
+			return id, true
+		}
+	}
+	return 0, false
 }
 
 // MockNoteRepository implements the NoteRepository interface with in-memory storage
 type MockNoteRepository struct {
-	notes map[int]domain.Note
-	mu    sync.RWMutex
+	notes  map[noteKey]domain.Note
+	mu     sync.RWMutex
+	nextID uint
+}
+
+// noteKey identifies a note page of an event and speaker
+type noteKey struct {
+	eventID    uint
+	speaker    string
+	pageNumber int
 }
 
 var _ repository.NoteRepository = &MockNoteRepository{}
@@ -230,14 +657,14 @@ var _ repository.NoteRepository = &MockNoteRepository{}
 // NewMockNoteRepository creates a new mock note repository
 func NewMockNoteRepository() *MockNoteRepository {
 	return &MockNoteRepository{
-		notes: map[int]domain.Note{
-			1: {
+		notes: map[noteKey]domain.Note{
+			{pageNumber: 1}: {
 				ID:         1,
 				Content:    "Introduction to the talk",
 				PageNumber: 1,
 				TotalPages: 10,
 			},
-			2: {
+			{pageNumber: 2}: {
 				ID:         2,
 				Content:    "Key concepts and definitions",
 				PageNumber: 2,
@@ -245,11 +672,12 @@ func NewMockNoteRepository() *MockNoteRepository {
 			},
 			// Add more sample notes as needed
 		},
+		nextID: 3,
 	}
 }
 
-// GetNote returns a note for a specific page
-func (m *MockNoteRepository) GetNote(ctx context.Context, pageNumber int) (domain.Note, error) {
+// GetNote returns the note for a specific page of an event and speaker
+func (m *MockNoteRepository) GetNote(ctx context.Context, eventID uint, speaker string, pageNumber int) (domain.Note, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return domain.Note{}, ctx.Err()
@@ -258,14 +686,16 @@ func (m *MockNoteRepository) GetNote(ctx context.Context, pageNumber int) (domai
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if note, exists := m.notes[pageNumber]; exists {
+	if note, exists := m.notes[noteKey{eventID, speaker, pageNumber}]; exists {
 		return note, nil
 	}
 
 	// Return an empty note with the correct page number if not found
 	return domain.Note{
+		EventID:    eventID,
+		Speaker:    speaker,
 		PageNumber: pageNumber,
-		TotalPages: len(m.notes),
+		TotalPages: m.totalPages(eventID, speaker),
 	}, nil
 }
 
@@ -279,13 +709,41 @@ func (m *MockNoteRepository) SaveNote(ctx context.Context, note domain.Note) (bo
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.notes[note.PageNumber] = note
+	key := noteKey{note.EventID, note.Speaker, note.PageNumber}
+	if existing, exists := m.notes[key]; exists {
+		note.ID = existing.ID
+	} else {
+		note.ID = m.nextID
+		m.nextID++
+	}
+	m.notes[key] = note
+
+	// Pages added later are counted on every note of the same event and speaker
+	for key, existing := range m.notes {
+		if key.eventID == note.EventID && key.speaker == note.Speaker && existing.TotalPages < note.TotalPages {
+			existing.TotalPages = note.TotalPages
+			m.notes[key] = existing
+		}
+	}
 	return true, nil
 }
 
+// totalPages returns the highest page count or page number of the notes of an event and speaker.
+// The caller must hold the lock.
+func (m *MockNoteRepository) totalPages(eventID uint, speaker string) int {
+	total := 0
+	for key, note := range m.notes {
+		if key.eventID == eventID && key.speaker == speaker {
+			total = max(total, key.pageNumber, note.TotalPages)
+		}
+	}
+	return total
+}
+
 // MockQuestionRepository implements the QuestionRepository interface with in-memory storage
 type MockQuestionRepository struct {
 	questions []domain.Question
+	votes     map[uint]map[string]bool
 	mu        sync.RWMutex
 	nextID    uint
 }
@@ -296,12 +754,13 @@ var _ repository.QuestionRepository = &MockQuestionRepository{}
 func NewMockQuestionRepository() *MockQuestionRepository {
 	return &MockQuestionRepository{
 		questions: make([]domain.Question, 0),
+		votes:     make(map[uint]map[string]bool),
 		nextID:    1,
 	}
 }
 
-// GetQuestions returns all questions
-func (m *MockQuestionRepository) GetQuestions(ctx context.Context) ([]domain.Question, error) {
+// GetQuestions returns the questions of an event, newest first
+func (m *MockQuestionRepository) GetQuestions(ctx context.Context, eventID uint) ([]domain.Question, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -310,7 +769,11 @@ func (m *MockQuestionRepository) GetQuestions(ctx context.Context) ([]domain.Que
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.questions, nil
+	questions := m.eventQuestions(eventID)
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].SubmittedAt.After(questions[j].SubmittedAt)
+	})
+	return questions, nil
 }
 
 // AddQuestion adds a new question
@@ -325,13 +788,33 @@ func (m *MockQuestionRepository) AddQuestion(ctx context.Context, question domai
 
 	question.ID = m.nextID
 	question.SubmittedAt = time.Now()
-	question.Answered = false
+	question.State = domain.QuestionStatePending
+	question.MergedInto = 0
+	question.Votes = 0
 	m.nextID++
 	m.questions = append(m.questions, question)
 	return question, nil
 }
 
-// MarkAsAnswered marks a question as answered
+// GetQuestion returns a question by ID
+func (m *MockQuestionRepository) GetQuestion(ctx context.Context, id uint) (domain.Question, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Question{}, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, q := range m.questions {
+		if q.ID == id {
+			return q, nil
+		}
+	}
+	return domain.Question{}, fmt.Errorf("question %d: %w", id, repository.ErrNotFound)
+}
+
+// MarkAsAnswered marks a pending or pinned question as answered
 func (m *MockQuestionRepository) MarkAsAnswered(ctx context.Context, id uint) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -342,8 +825,605 @@ func (m *MockQuestionRepository) MarkAsAnswered(ctx context.Context, id uint) (b
 	defer m.mu.Unlock()
 
 	for i, q := range m.questions {
-		if q.ID == id {
-			m.questions[i].Answered = true
+		if q.ID == id && q.IsOpen() {
+			m.questions[i].State = domain.QuestionStateAnswered
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpdateQuestion saves the name and content of a question
+func (m *MockQuestionRepository) UpdateQuestion(ctx context.Context, question domain.Question) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, q := range m.questions {
+		if q.ID == question.ID {
+			m.questions[i].Name = question.Name
+			m.questions[i].Content = question.Content
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetQuestionState moves a question from one moderation state to another if nobody else moved it since it was read
+func (m *MockQuestionRepository) SetQuestionState(ctx context.Context, id uint, from domain.QuestionState, to domain.QuestionState) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.questionIndex(id)
+	if index < 0 {
+		return false, nil
+	}
+	if m.questions[index].State != from {
+		return false, fmt.Errorf("question %d is no longer %s: %w", id, from, repository.ErrConflict)
+	}
+
+	m.questions[index].State = to
+	return true, nil
+}
+
+// PinQuestion pins a question as the one being answered now and marks the question pinned before it answered
+func (m *MockQuestionRepository) PinQuestion(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.questionIndex(id)
+	if index < 0 {
+		return false, nil
+	}
+
+	eventID := m.questions[index].EventID
+	for i, q := range m.questions {
+		if q.EventID == eventID && q.State == domain.QuestionStatePinned {
+			m.questions[i].State = domain.QuestionStateAnswered
+		}
+	}
+	m.questions[index].State = domain.QuestionStatePinned
+	return true, nil
+}
+
+// MergeQuestions folds the source question into the target question and moves its votes
+func (m *MockQuestionRepository) MergeQuestions(ctx context.Context, sourceID uint, targetID uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	source := m.questionIndex(sourceID)
+	if source < 0 {
+		return false, nil
+	}
+	m.questions[source].State = domain.QuestionStateMerged
+	m.questions[source].MergedInto = targetID
+
+	if m.votes[targetID] == nil {
+		m.votes[targetID] = make(map[string]bool)
+	}
+	for voterID := range m.votes[sourceID] {
+		m.votes[targetID][voterID] = true
+	}
+	if target := m.questionIndex(targetID); target >= 0 {
+		m.questions[target].Votes = len(m.votes[targetID])
+	}
+	return true, nil
+}
+
+// GetRankedQuestions returns the questions of an event with the most votes first, then the oldest first
+func (m *MockQuestionRepository) GetRankedQuestions(ctx context.Context, eventID uint) ([]domain.Question, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	questions := m.eventQuestions(eventID)
+	sort.SliceStable(questions, func(i, j int) bool {
+		if questions[i].Votes != questions[j].Votes {
+			return questions[i].Votes > questions[j].Votes
+		}
+		return questions[i].SubmittedAt.Before(questions[j].SubmittedAt)
+	})
+	return questions, nil
+}
+
+// Upvote adds the vote of a voter to a question
+func (m *MockQuestionRepository) Upvote(ctx context.Context, questionID uint, voterID string) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, q := range m.questions {
+		if q.ID != questionID {
+			continue
+		}
+
+		if m.votes[questionID][voterID] {
+			return false, fmt.Errorf("vote of %q for question %d: %w", voterID, questionID, repository.ErrAlreadyExists)
+		}
+		if m.votes[questionID] == nil {
+			m.votes[questionID] = make(map[string]bool)
+		}
+		m.votes[questionID][voterID] = true
+		m.questions[i].Votes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// GetVotedQuestionIDs returns the IDs of the questions a voter voted for
+func (m *MockQuestionRepository) GetVotedQuestionIDs(ctx context.Context, voterID string) ([]uint, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]uint, 0)
+	for questionID, voters := range m.votes {
+		if voters[voterID] {
+			ids = append(ids, questionID)
+		}
+	}
+	return ids, nil
+}
+
+// questionIndex returns the position of a question in the list, or -1 if it doesn't exist.
+// The caller must hold the lock.
+func (m *MockQuestionRepository) questionIndex(id uint) int {
+	for i, q := range m.questions {
+		if q.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// eventQuestions returns a copy of the questions of an event.
+// The caller must hold the lock.
+func (m *MockQuestionRepository) eventQuestions(eventID uint) []domain.Question {
+	questions := make([]domain.Question, 0)
+	for _, question := range m.questions {
+		if question.EventID == eventID {
+			questions = append(questions, question)
+		}
+	}
+	return questions
+}
+
+// MockAgendaRepository implements the AgendaRepository interface with in-memory storage
+type MockAgendaRepository struct {
+	agendas map[uint]domain.Agenda
+	mu      sync.Mutex
+	nextID  uint
+}
+
+var _ repository.AgendaRepository = &MockAgendaRepository{}
+
+// NewMockAgendaRepository creates a new mock agenda repository
+func NewMockAgendaRepository() *MockAgendaRepository {
+	return &MockAgendaRepository{
+		agendas: make(map[uint]domain.Agenda),
+		nextID:  1,
+	}
+}
+
+// GetAgenda returns the agenda of an event with its segments in order
+func (m *MockAgendaRepository) GetAgenda(ctx context.Context, eventID uint) (domain.Agenda, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Agenda{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	agenda, exists := m.agendas[eventID]
+	if !exists {
+		return domain.Agenda{}, fmt.Errorf("agenda for event %d: %w", eventID, repository.ErrNotFound)
+	}
+
+	agenda.Segments = append([]domain.AgendaSegment(nil), agenda.Segments...)
+	return agenda, nil
+}
+
+// SaveAgenda creates or replaces the agenda of an event, including all its segments
+func (m *MockAgendaRepository) SaveAgenda(ctx context.Context, agenda domain.Agenda) (domain.Agenda, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Agenda{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, exists := m.agendas[agenda.EventID]; exists {
+		agenda.ID = existing.ID
+	} else {
+		agenda.ID = m.nextID
+		m.nextID++
+	}
+
+	agenda.Segments = append([]domain.AgendaSegment(nil), agenda.Segments...)
+	m.agendas[agenda.EventID] = agenda
+	return agenda, nil
+}
+
+// SetCurrentSegment moves the agenda of an event from one segment to another
+func (m *MockAgendaRepository) SetCurrentSegment(ctx context.Context, eventID uint, from int, to int) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	agenda, exists := m.agendas[eventID]
+	if !exists {
+		return false, nil
+	}
+	if agenda.CurrentIndex != from {
+		return false, fmt.Errorf("agenda for event %d is not at segment %d: %w", eventID, from, repository.ErrConflict)
+	}
+
+	agenda.CurrentIndex = to
+	m.agendas[eventID] = agenda
+	return true, nil
+}
+
+// MockTimerLogRepository implements the TimerLogRepository interface with in-memory storage
+type MockTimerLogRepository struct {
+	entries []domain.TimerLogEntry
+	mu      sync.RWMutex
+	nextID  uint
+}
+
+var _ repository.TimerLogRepository = &MockTimerLogRepository{}
+
+// NewMockTimerLogRepository creates a new mock timer log repository
+func NewMockTimerLogRepository() *MockTimerLogRepository {
+	return &MockTimerLogRepository{
+		entries: make([]domain.TimerLogEntry, 0),
+		nextID:  1,
+	}
+}
+
+// AddTimerLogEntry appends an entry to the timer log and returns it with an ID
+func (m *MockTimerLogRepository) AddTimerLogEntry(ctx context.Context, entry domain.TimerLogEntry) (domain.TimerLogEntry, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.TimerLogEntry{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry.ID = m.nextID
+	m.nextID++
+	m.entries = append(m.entries, entry)
+	return entry, nil
+}
+
+// GetEventTimerLog returns the log entries of all timers of an event in the order they happened
+func (m *MockTimerLogRepository) GetEventTimerLog(ctx context.Context, eventID uint) ([]domain.TimerLogEntry, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]domain.TimerLogEntry, 0)
+	for _, entry := range m.entries {
+		if entry.EventID == eventID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt.Before(entries[j].OccurredAt)
+	})
+	return entries, nil
+}
+
+// MockSeriesRepository implements the SeriesRepository interface with in-memory storage
+type MockSeriesRepository struct {
+	series []domain.Series
+	mu     sync.RWMutex
+	nextID uint
+}
+
+var _ repository.SeriesRepository = &MockSeriesRepository{}
+
+// NewMockSeriesRepository creates a new mock series repository without any series
+func NewMockSeriesRepository() *MockSeriesRepository {
+	return &MockSeriesRepository{
+		series: make([]domain.Series, 0),
+		nextID: 1,
+	}
+}
+
+// GetAllSeries returns all series, oldest first
+func (m *MockSeriesRepository) GetAllSeries(ctx context.Context) ([]domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	series := make([]domain.Series, len(m.series))
+	copy(series, m.series)
+	return series, nil
+}
+
+// GetSeries returns a series by ID
+func (m *MockSeriesRepository) GetSeries(ctx context.Context, id uint) (domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Series{}, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, series := range m.series {
+		if series.ID == id {
+			return series, nil
+		}
+	}
+	return domain.Series{}, fmt.Errorf("series %d: %w", id, repository.ErrNotFound)
+}
+
+// AddSeries adds a new series and returns it with an ID
+func (m *MockSeriesRepository) AddSeries(ctx context.Context, series domain.Series) (domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Series{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series.ID = m.nextID
+	m.nextID++
+	m.series = append(m.series, series)
+	return series, nil
+}
+
+// DeleteSeries removes a series
+func (m *MockSeriesRepository) DeleteSeries(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, series := range m.series {
+		if series.ID == id {
+			m.series = append(m.series[:i], m.series[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockSpeakerRepository implements the SpeakerRepository interface on the speakers of a MockEventRepository
+type MockSpeakerRepository struct {
+	events *MockEventRepository
+}
+
+var _ repository.SpeakerRepository = &MockSpeakerRepository{}
+
+// NewMockSpeakerRepository creates a new mock speaker repository for the speakers of the events in events
+func NewMockSpeakerRepository(events *MockEventRepository) *MockSpeakerRepository {
+	return &MockSpeakerRepository{
+		events: events,
+	}
+}
+
+// GetAllSpeakers returns all speakers ordered by name
+func (m *MockSpeakerRepository) GetAllSpeakers(ctx context.Context) ([]domain.Speaker, error) {
+	return m.SearchSpeakers(ctx, "", -1)
+}
+
+// GetSpeaker returns a speaker by ID
+func (m *MockSpeakerRepository) GetSpeaker(ctx context.Context, id uint) (domain.Speaker, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Speaker{}, ctx.Err()
+	}
+
+	m.events.mu.RLock()
+	defer m.events.mu.RUnlock()
+
+	for _, speaker := range m.events.speakers {
+		if speaker.ID == id {
+			return speaker, nil
+		}
+	}
+	return domain.Speaker{}, fmt.Errorf("speaker %d: %w", id, repository.ErrNotFound)
+}
+
+// SearchSpeakers returns up to limit speakers whose name contains search, ignoring case, ordered by name.
+// A negative limit returns all of them.
+func (m *MockSpeakerRepository) SearchSpeakers(ctx context.Context, search string, limit int) ([]domain.Speaker, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.events.mu.RLock()
+	defer m.events.mu.RUnlock()
+
+	search = strings.ToLower(strings.TrimSpace(search))
+	speakers := make([]domain.Speaker, 0)
+	for _, speaker := range m.events.speakers {
+		if strings.Contains(strings.ToLower(speaker.Name), search) {
+			speakers = append(speakers, speaker)
+		}
+	}
+	sort.SliceStable(speakers, func(i, j int) bool {
+		return strings.ToLower(speakers[i].Name) < strings.ToLower(speakers[j].Name)
+	})
+	if limit >= 0 && len(speakers) > limit {
+		speakers = speakers[:limit]
+	}
+	return speakers, nil
+}
+
+// UpdateSpeaker updates the profile of a speaker and the speakers of their events
+func (m *MockSpeakerRepository) UpdateSpeaker(ctx context.Context, speaker domain.Speaker) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.events.mu.Lock()
+	defer m.events.mu.Unlock()
+
+	if m.events.speakerIndex(speaker.Name, speaker.ID) >= 0 {
+		return false, fmt.Errorf("speaker %q: %w", speaker.Name, repository.ErrConflict)
+	}
+
+	for i, s := range m.events.speakers {
+		if s.ID != speaker.ID {
+			continue
+		}
+		m.events.speakers[i] = speaker
+
+		// Events hold copies of their speakers
+		for j, event := range m.events.events {
+			for k, eventSpeaker := range event.Speakers {
+				if eventSpeaker.ID == speaker.ID {
+					speakers := append([]domain.Speaker(nil), event.Speakers...)
+					speakers[k] = speaker
+					m.events.events[j].Speakers = speakers
+					m.events.events[j].Speaker = domain.SpeakerLine(speakers)
+				}
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// MockResourceRepository implements the ResourceRepository interface with in-memory storage
+type MockResourceRepository struct {
+	resources []domain.Resource
+	mu        sync.RWMutex
+	nextID    uint
+}
+
+var _ repository.ResourceRepository = &MockResourceRepository{}
+
+// NewMockResourceRepository creates a new mock resource repository without any resources
+func NewMockResourceRepository() *MockResourceRepository {
+	return &MockResourceRepository{
+		resources: make([]domain.Resource, 0),
+		nextID:    1,
+	}
+}
+
+// GetEventResources returns the resources of an event in the order they were added
+func (m *MockResourceRepository) GetEventResources(ctx context.Context, eventID uint) ([]domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	resources := make([]domain.Resource, 0)
+	for _, resource := range m.resources {
+		if resource.EventID == eventID {
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// GetResource returns a resource by ID
+func (m *MockResourceRepository) GetResource(ctx context.Context, id uint) (domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Resource{}, ctx.Err()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, resource := range m.resources {
+		if resource.ID == id {
+			return resource, nil
+		}
+	}
+	return domain.Resource{}, fmt.Errorf("resource %d: %w", id, repository.ErrNotFound)
+}
+
+// AddResource adds a new resource and returns it with an ID
+func (m *MockResourceRepository) AddResource(ctx context.Context, resource domain.Resource) (domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Resource{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	resource.ID = m.nextID
+	resource.AddedAt = time.Now()
+	m.nextID++
+	m.resources = append(m.resources, resource)
+	return resource, nil
+}
+
+// DeleteResource removes a resource
+func (m *MockResourceRepository) DeleteResource(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, resource := range m.resources {
+		if resource.ID == id {
+			m.resources = append(m.resources[:i], m.resources[i+1:]...)
 			return true, nil
 		}
 	}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AgendaRepository implements the repository.AgendaRepository interface using GORM
+type AgendaRepository struct {
+	db *gorm.DB
+}
+
+// Ensure AgendaRepository implements repository.AgendaRepository
+var _ repository.AgendaRepository = &AgendaRepository{}
+
+// NewAgendaRepository creates a new agenda repository
+func NewAgendaRepository(dbManager *DBManager) *AgendaRepository {
+	return &AgendaRepository{
+		db: dbManager.GetDB(),
+	}
+}
+
+// GetAgenda returns the agenda of an event with its segments in order
+func (r *AgendaRepository) GetAgenda(ctx context.Context, eventID uint) (domain.Agenda, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Agenda{}, ctx.Err()
+	}
+
+	var model AgendaModel
+	result := r.db.WithContext(ctx).
+		Preload("Segments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
+		}).
+		Where("event_id = ?", eventID).
+		First(&model)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Agenda{}, fmt.Errorf("agenda for event %d: %w", eventID, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Agenda{}, fmt.Errorf("failed to get agenda: %w", result.Error)
+	}
+
+	return convertAgendaModelToDomain(model), nil
+}
+
+// SaveAgenda creates or replaces the agenda of an event, including all its segments
+func (r *AgendaRepository) SaveAgenda(ctx context.Context, agenda domain.Agenda) (domain.Agenda, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Agenda{}, ctx.Err()
+	}
+
+	var saved AgendaModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model AgendaModel
+		result := tx.Where("event_id = ?", agenda.EventID).First(&model)
+		if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get agenda: %w", result.Error)
+		}
+
+		model.EventID = agenda.EventID
+		model.CurrentSegment = agenda.CurrentIndex
+		model.AutoAdvance = agenda.AutoAdvance
+		model.Segments = nil
+		if err := tx.Save(&model).Error; err != nil {
+			return fmt.Errorf("failed to save agenda: %w", err)
+		}
+
+		// Segments are replaced as a whole to keep their positions consistent
+		if err := tx.Unscoped().Where("agenda_id = ?", model.ID).Delete(&AgendaSegmentModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete old agenda segments: %w", err)
+		}
+
+		segments := convertDomainToAgendaSegmentModels(model.ID, agenda.Segments)
+		if len(segments) > 0 {
+			if err := tx.Create(&segments).Error; err != nil {
+				return fmt.Errorf("failed to create agenda segments: %w", err)
+			}
+		}
+
+		model.Segments = segments
+		saved = model
+		return nil
+	})
+	if err != nil {
+		return domain.Agenda{}, err
+	}
+
+	return convertAgendaModelToDomain(saved), nil
+}
+
+// SetCurrentSegment moves the agenda of an event from one segment to another
+func (r *AgendaRepository) SetCurrentSegment(ctx context.Context, eventID uint, from int, to int) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Model(&AgendaModel{}).
+		Where("event_id = ? AND current_segment = ?", eventID, from).
+		Update("current_segment", to)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set current segment: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Nothing was updated, either because there is no agenda or because it moved on
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&AgendaModel{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check agenda: %w", err)
+	}
+	if count > 0 {
+		return false, fmt.Errorf("agenda for event %d is not at segment %d: %w", eventID, from, repository.ErrConflict)
+	}
+
+	return false, nil
+}
+
+// Helper functions for conversion between domain and model
+
+// convertAgendaModelToDomain converts an AgendaModel to a domain.Agenda
+func convertAgendaModelToDomain(model AgendaModel) domain.Agenda {
+	segments := make([]domain.AgendaSegment, len(model.Segments))
+	for i, segment := range model.Segments {
+		segments[i] = domain.AgendaSegment{
+			Name:     segment.Name,
+			Duration: time.Duration(segment.Duration),
+		}
+	}
+
+	return domain.Agenda{
+		ID:           model.Model.ID,
+		EventID:      model.EventID,
+		Segments:     segments,
+		CurrentIndex: model.CurrentSegment,
+		AutoAdvance:  model.AutoAdvance,
+	}
+}
+
+// convertDomainToAgendaSegmentModels converts agenda segments to AgendaSegmentModels, keeping their order
+func convertDomainToAgendaSegmentModels(agendaID uint, segments []domain.AgendaSegment) []AgendaSegmentModel {
+	models := make([]AgendaSegmentModel, len(segments))
+	for i, segment := range segments {
+		models[i] = AgendaSegmentModel{
+			AgendaID: agendaID,
+			Position: i,
+			Name:     segment.Name,
+			Duration: segment.Duration.Nanoseconds(),
+		}
+	}
+	return models
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -12,10 +13,15 @@ import (
 // DBManager manages the database connection
 type DBManager struct {
 	db *gorm.DB
+	// timeZone is the group's timezone, given to events stored before they had one
+	timeZone *time.Location
+	// fullTextSearch tells whether events can be searched with FTS5
+	fullTextSearch bool
 }
 
-// NewDBManager creates a new database manager
-func NewDBManager(dbPath string) (*DBManager, error) {
+// NewDBManager creates a new database manager.
+// Events stored without a timezone are moved to timeZone while migrating.
+func NewDBManager(dbPath string, timeZone *time.Location) (*DBManager, error) {
 	// Configure GORM
 	config := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
@@ -29,7 +35,8 @@ func NewDBManager(dbPath string) (*DBManager, error) {
 
 	// Create a new DBManager
 	manager := &DBManager{
-		db: db,
+		db:       db,
+		timeZone: timeZone,
 	}
 
 	// Auto migrate the schema
@@ -47,14 +54,51 @@ func (m *DBManager) Migrate() error {
 	// Auto migrate all models
 	err := m.db.AutoMigrate(
 		&EventModel{},
+		&SeriesModel{},
+		&SpeakerModel{},
+		&EventSpeakerModel{},
+		&ResourceModel{},
 		&TimerModel{},
+		&TimerLogModel{},
+		&AgendaModel{},
+		&AgendaSegmentModel{},
 		&NoteModel{},
 		&QuestionModel{},
+		&QuestionVoteModel{},
 	)
 	if err != nil {
 		return fmt.Errorf("auto migration failed: %w", err)
 	}
 
+	// Run data migrations
+	if err := m.migrateTimerState(); err != nil {
+		return fmt.Errorf("timer state migration failed: %w", err)
+	}
+	if err := m.migrateQuestionState(); err != nil {
+		return fmt.Errorf("question state migration failed: %w", err)
+	}
+	if err := m.migrateEventUpcoming(); err != nil {
+		return fmt.Errorf("event upcoming migration failed: %w", err)
+	}
+
+	fullTextSearch, err := m.setupEventSearch()
+	if err != nil {
+		return fmt.Errorf("event search setup failed: %w", err)
+	}
+	m.fullTextSearch = fullTextSearch
+
+	// Runs after the search setup, which removes search triggers that fail without FTS5
+	if err := m.migrateEventTimeZone(); err != nil {
+		return fmt.Errorf("event timezone migration failed: %w", err)
+	}
+	// Runs after the timezone migration, which moves event dates
+	if err := m.migrateEventEndsAt(); err != nil {
+		return fmt.Errorf("event end migration failed: %w", err)
+	}
+	if err := m.migrateEventSpeakers(); err != nil {
+		return fmt.Errorf("event speaker migration failed: %w", err)
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
 }
@@ -3,29 +3,42 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// EventRepository implements the repository.EventRepository interface using GORM
+// errSlotNotOpen rolls back a claim of a slot that isn't open
+var errSlotNotOpen = errors.New("slot isn't open")
+
+// EventRepository implements the repository.EventRepository interface using GORM.
+// Whether an event is upcoming or past is decided by its end against the clock.
 type EventRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	clock clock.Clock
+	// fullTextSearch tells whether SearchPastEvents can use the FTS5 index
+	fullTextSearch bool
 }
 
 // Ensure EventRepository implements repository.EventRepository
 var _ repository.EventRepository = &EventRepository{}
 
 // NewEventRepository creates a new event repository
-func NewEventRepository(dbManager *DBManager) *EventRepository {
+func NewEventRepository(dbManager *DBManager, clock clock.Clock) *EventRepository {
 	return &EventRepository{
-		db: dbManager.GetDB(),
+		db:             dbManager.GetDB(),
+		clock:          clock,
+		fullTextSearch: dbManager.fullTextSearch,
 	}
 }
 
-// GetUpcomingEvents returns all upcoming events
+// GetUpcomingEvents returns the events that aren't over yet, soonest first
 func (r *EventRepository) GetUpcomingEvents(ctx context.Context) ([]domain.Event, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -33,20 +46,15 @@ func (r *EventRepository) GetUpcomingEvents(ctx context.Context) ([]domain.Event
 	}
 
 	var models []EventModel
-	if err := r.db.WithContext(ctx).Where("is_upcoming = ?", true).Find(&models).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("ends_at > ?", r.now()).Order("date asc").Find(&models).Error; err != nil {
 		return nil, fmt.Errorf("failed to get upcoming events: %w", err)
 	}
 
-	// Convert models to domain entities
-	events := make([]domain.Event, len(models))
-	for i, model := range models {
-		events[i] = convertEventModelToDomain(model)
-	}
-
-	return events, nil
+	// Convert models to domain entities with their speakers
+	return r.convertEventModels(ctx, models)
 }
 
-// GetPastEvents returns all past events
+// GetPastEvents returns the events that are over, newest first, leaving out unclaimed slots
 func (r *EventRepository) GetPastEvents(ctx context.Context) ([]domain.Event, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -54,17 +62,82 @@ func (r *EventRepository) GetPastEvents(ctx context.Context) ([]domain.Event, er
 	}
 
 	var models []EventModel
-	if err := r.db.WithContext(ctx).Where("is_upcoming = ?", false).Find(&models).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("ends_at <= ? AND open = ?", r.now(), false).Order("date desc, id desc").Find(&models).Error; err != nil {
 		return nil, fmt.Errorf("failed to get past events: %w", err)
 	}
 
-	// Convert models to domain entities
-	events := make([]domain.Event, len(models))
-	for i, model := range models {
-		events[i] = convertEventModelToDomain(model)
+	// Convert models to domain entities with their speakers
+	return r.convertEventModels(ctx, models)
+}
+
+// SearchPastEvents returns a page of past events newest first, matching search when it isn't empty
+func (r *EventRepository) SearchPastEvents(ctx context.Context, search string, after *repository.EventCursor, limit int) ([]domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	return events, nil
+	query := r.db.WithContext(ctx).Where("ends_at <= ? AND open = ?", r.now(), false)
+
+	// Every word of the search has to match, as a word prefix with FTS5 and anywhere without it
+	words := strings.Fields(search)
+	if len(words) > 0 && r.fullTextSearch {
+		query = query.Where("id IN (SELECT rowid FROM events_fts WHERE events_fts MATCH ?)", ftsQuery(words))
+	} else {
+		for _, word := range words {
+			pattern := likePattern(word)
+			query = query.Where(`(title LIKE ? ESCAPE '\' OR speaker LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`, pattern, pattern, pattern)
+		}
+	}
+
+	if after != nil {
+		date := after.Date.UTC()
+		query = query.Where("(date < ? OR (date = ? AND id < ?))", date, date, after.ID)
+	}
+
+	var models []EventModel
+	if err := query.Order("date desc, id desc").Limit(limit).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to search past events: %w", err)
+	}
+
+	// Convert models to domain entities with their speakers
+	return r.convertEventModels(ctx, models)
+}
+
+// GetEvent returns an event by ID
+func (r *EventRepository) GetEvent(ctx context.Context, id uint) (domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Event{}, ctx.Err()
+	}
+
+	var model EventModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Event{}, fmt.Errorf("event %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Event{}, fmt.Errorf("failed to get event: %w", result.Error)
+	}
+
+	return r.convertEventModel(ctx, model)
+}
+
+// GetEventByUID returns the event imported with an iCalendar UID
+func (r *EventRepository) GetEventByUID(ctx context.Context, uid string) (domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Event{}, ctx.Err()
+	}
+
+	var model EventModel
+	result := r.db.WithContext(ctx).Where("uid = ? AND uid != ''", uid).First(&model)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Event{}, fmt.Errorf("event %q: %w", uid, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Event{}, fmt.Errorf("failed to get event: %w", result.Error)
+	}
+
+	return r.convertEventModel(ctx, model)
 }
 
 // AddEvent adds a new event and returns it with an ID
@@ -77,51 +150,258 @@ func (r *EventRepository) AddEvent(ctx context.Context, event domain.Event) (dom
 	// Convert domain entity to model
 	model := convertDomainToEventModel(event)
 
-	// Save to database
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
-		return domain.Event{}, fmt.Errorf("failed to add event: %w", err)
+	// Save to database together with the links to its speakers
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		speakers, err := findOrAddSpeakers(tx, event.SpeakerList())
+		if err != nil {
+			return err
+		}
+		model.Speaker = speakerLine(speakers)
+
+		if err := tx.Create(&model).Error; err != nil {
+			return fmt.Errorf("failed to add event: %w", err)
+		}
+		return setEventSpeakers(tx, model.ID, speakers)
+	})
+	if err != nil {
+		return domain.Event{}, err
 	}
 
 	// Return the event with the new ID
-	return convertEventModelToDomain(model), nil
+	return r.convertEventModel(ctx, model)
 }
 
-// UpdateEvent updates an existing event
+// UpdateEvent updates an existing event and counts up its sequence
 func (r *EventRepository) UpdateEvent(ctx context.Context, event domain.Event) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return false, ctx.Err()
 	}
 
-	// Convert domain entity to model
-	model := convertDomainToEventModel(event)
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		speakers, err := findOrAddSpeakers(tx, event.SpeakerList())
+		if err != nil {
+			return err
+		}
+
+		// Update in database. Unlike Save, Updates never creates the event or brings back a deleted one.
+		result := tx.Model(&EventModel{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+			"title":       event.Title,
+			"speaker":     speakerLine(speakers),
+			"description": event.Description,
+			"date":        event.Date.UTC(),
+			"time_zone":   event.TimeZone,
+			"duration":    int64(event.Duration),
+			"ends_at":     event.End().UTC(),
+			"sequence":    gorm.Expr("sequence + 1"),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update event: %w", result.Error)
+		}
+
+		// Check if any rows were affected
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		return setEventSpeakers(tx, event.ID, speakers)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return updated, nil
+}
 
-	// Update in database
-	result := r.db.WithContext(ctx).Save(&model)
+// DeleteEvent soft deletes an event by setting its DeletedAt
+func (r *EventRepository) DeleteEvent(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Delete(&EventModel{}, id)
 	if result.Error != nil {
-		return false, fmt.Errorf("failed to update event: %w", result.Error)
+		return false, fmt.Errorf("failed to delete event: %w", result.Error)
 	}
 
 	// Check if any rows were affected
 	return result.RowsAffected > 0, nil
 }
 
+// AddSeriesSlots adds the open slots of a series that weren't generated before
+func (r *EventRepository) AddSeriesSlots(ctx context.Context, slots []domain.Event) (int, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	added := 0
+	for _, slot := range slots {
+		model := convertDomainToEventModel(slot)
+
+		// The unique series occurrence index also covers deleted slots, so they stay deleted
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return added, fmt.Errorf("failed to add series slot: %w", result.Error)
+		}
+		added += int(result.RowsAffected)
+	}
+
+	return added, nil
+}
+
+// ClaimSlot turns an open slot into a talk, as long as nobody claimed it first
+func (r *EventRepository) ClaimSlot(ctx context.Context, event domain.Event) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		speakers, err := findOrAddSpeakers(tx, event.SpeakerList())
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&EventModel{}).Where("id = ? AND open = ?", event.ID, true).Updates(map[string]interface{}{
+			"title":       event.Title,
+			"speaker":     speakerLine(speakers),
+			"description": event.Description,
+			"open":        false,
+			"sequence":    gorm.Expr("sequence + 1"),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to claim slot: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Rolls back the speakers added for the claim
+			return errSlotNotOpen
+		}
+		claimed = true
+		return setEventSpeakers(tx, event.ID, speakers)
+	})
+	if claimed {
+		return true, nil
+	} else if err != nil && !errors.Is(err, errSlotNotOpen) {
+		return false, err
+	}
+
+	// Tell a slot that doesn't exist from one that was claimed already
+	_, err = r.GetEvent(ctx, event.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, fmt.Errorf("event %d isn't an open slot: %w", event.ID, repository.ErrConflict)
+}
+
+// DeleteOpenSlots soft deletes the open slots of a series that haven't started yet
+func (r *EventRepository) DeleteOpenSlots(ctx context.Context, seriesID uint) (int, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Where("series_id = ? AND open = ? AND date > ?", seriesID, true, r.now()).Delete(&EventModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete open slots: %w", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// GetSpeakerEvents returns the events a speaker gives or gave, soonest first
+func (r *EventRepository) GetSpeakerEvents(ctx context.Context, speakerID uint) ([]domain.Event, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []EventModel
+	err := r.db.WithContext(ctx).
+		Where("id IN (SELECT event_id FROM event_speakers WHERE speaker_id = ?)", speakerID).
+		Order("date asc").
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get speaker events: %w", err)
+	}
+
+	// Convert models to domain entities with their speakers
+	return r.convertEventModels(ctx, models)
+}
+
+// convertEventModel converts a model to a domain entity with its speakers
+func (r *EventRepository) convertEventModel(ctx context.Context, model EventModel) (domain.Event, error) {
+	events, err := r.convertEventModels(ctx, []EventModel{model})
+	if err != nil {
+		return domain.Event{}, err
+	}
+	return events[0], nil
+}
+
+// convertEventModels converts models to domain entities with their speakers
+func (r *EventRepository) convertEventModels(ctx context.Context, models []EventModel) ([]domain.Event, error) {
+	events := make([]domain.Event, len(models))
+	for i, model := range models {
+		events[i] = convertEventModelToDomain(model)
+	}
+
+	if err := loadEventSpeakers(r.db.WithContext(ctx), events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// now returns the current time of the clock in UTC.
+// Dates are stored in UTC, so comparing them as text in SQLite keeps their order.
+func (r *EventRepository) now() time.Time {
+	return r.clock.Now().UTC()
+}
+
 // Helper functions for conversion between domain and model
 
 // convertEventModelToDomain converts an EventModel to a domain.Event
 func convertEventModelToDomain(model EventModel) domain.Event {
+	var seriesID uint
+	if model.SeriesID != nil {
+		seriesID = *model.SeriesID
+	}
+	var occurrence time.Time
+	if model.Occurrence != nil {
+		occurrence = *model.Occurrence
+	}
+
 	return domain.Event{
 		ID:          model.Model.ID,
 		Title:       model.Title,
 		Speaker:     model.Speaker,
 		Description: model.Description,
 		Date:        model.Date,
-		IsUpcoming:  model.IsUpcoming,
+		TimeZone:    model.TimeZone,
+		Duration:    time.Duration(model.Duration),
+		Sequence:    model.Sequence,
+		UpdatedAt:   model.UpdatedAt,
+		UID:         model.UID,
+		SeriesID:    seriesID,
+		Occurrence:  occurrence,
+		Open:        model.Open,
 	}
 }
 
 // convertDomainToEventModel converts a domain.Event to an EventModel
 func convertDomainToEventModel(event domain.Event) EventModel {
+	var seriesID *uint
+	var occurrence *time.Time
+	if event.SeriesID != 0 {
+		occurrenceUTC := event.Occurrence.UTC()
+		seriesID = &event.SeriesID
+		occurrence = &occurrenceUTC
+	}
+
 	return EventModel{
 		Model: gorm.Model{
 			ID:        event.ID,
@@ -131,7 +411,13 @@ func convertDomainToEventModel(event domain.Event) EventModel {
 		Title:       event.Title,
 		Speaker:     event.Speaker,
 		Description: event.Description,
-		Date:        event.Date,
-		IsUpcoming:  event.IsUpcoming,
+		Date:        event.Date.UTC(),
+		TimeZone:    event.TimeZone,
+		UID:         event.UID,
+		Duration:    int64(event.Duration),
+		EndsAt:      event.End().UTC(),
+		SeriesID:    seriesID,
+		Occurrence:  occurrence,
+		Open:        event.Open,
 	}
 }
@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+)
+
+// testNow is the pinned time of the event repository tests
+var testNow = time.Date(2025, 3, 6, 12, 0, 0, 0, time.UTC)
+
+// newTestDB returns a migrated database in a temporary directory
+func newTestDB(t *testing.T) *DBManager {
+	t.Helper()
+
+	dbManager, err := NewDBManager(filepath.Join(t.TempDir(), "test.db"), time.UTC)
+	if err != nil {
+		t.Fatalf("NewDBManager: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = dbManager.Close()
+	})
+	return dbManager
+}
+
+// addEvents adds events to the repository and returns them with their IDs
+func addEvents(t *testing.T, repo *EventRepository, events ...domain.Event) []domain.Event {
+	t.Helper()
+
+	added := make([]domain.Event, len(events))
+	for i, event := range events {
+		if event.TimeZone == "" {
+			event.TimeZone = "UTC"
+		}
+		if event.Duration == 0 {
+			event.Duration = time.Hour
+		}
+		var err error
+		added[i], err = repo.AddEvent(context.Background(), event)
+		if err != nil {
+			t.Fatalf("AddEvent %q: %v", event.Title, err)
+		}
+	}
+	return added
+}
+
+// pastTalks are past events for the archive tests, newest first
+func pastTalks() []domain.Event {
+	return []domain.Event{
+		{Title: "Transformers Explained", Speaker: "Ada Lovelace", Description: "Attention from scratch", Date: testNow.AddDate(0, 0, -7)},
+		{Title: "Vector Databases", Speaker: "Alan Turing", Description: "Embeddings and nearest neighbours", Date: testNow.AddDate(0, 0, -14)},
+		{Title: "Agents in Production", Speaker: "Grace Hopper", Description: "Tool use with transformers", Date: testNow.AddDate(0, 0, -21)},
+		{Title: "Prompt Engineering", Speaker: "Ada Lovelace", Description: "Few-shot examples", Date: testNow.AddDate(0, 0, -28)},
+		{Title: "Fine-tuning 100% Offline", Speaker: "Alan Turing", Description: "Small models on laptops", Date: testNow.AddDate(0, 0, -35)},
+	}
+}
+
+// titles returns the titles of events
+func titles(events []domain.Event) []string {
+	titles := make([]string, len(events))
+	for i, event := range events {
+		titles[i] = event.Title
+	}
+	return titles
+}
+
+// sameTitles reports whether events have the given titles in order
+func sameTitles(events []domain.Event, want ...string) bool {
+	got := titles(events)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchPastEvents(t *testing.T) {
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"", []string{"Transformers Explained", "Vector Databases", "Agents in Production", "Prompt Engineering", "Fine-tuning 100% Offline"}},
+		{"transform", []string{"Transformers Explained", "Agents in Production"}},
+		{"ada", []string{"Transformers Explained", "Prompt Engineering"}},
+		{"vector embed", []string{"Vector Databases"}},
+		{"100%", []string{"Fine-tuning 100% Offline"}},
+		{"quantum", nil},
+	}
+
+	for _, fullTextSearch := range []bool{true, false} {
+		name := "like"
+		if fullTextSearch {
+			name = "fts5"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			dbManager := newTestDB(t)
+			if fullTextSearch && !dbManager.fullTextSearch {
+				t.Skip("SQLite was built without FTS5, run the tests with -tags sqlite_fts5")
+			}
+			repo := NewEventRepository(dbManager, clock.Fixed(testNow))
+			repo.fullTextSearch = fullTextSearch
+
+			addEvents(t, repo, pastTalks()...)
+			addEvents(t, repo, domain.Event{Title: "Transformers Next Week", Speaker: "Ada Lovelace", Date: testNow.AddDate(0, 0, 7)})
+
+			for _, test := range tests {
+				events, err := repo.SearchPastEvents(context.Background(), test.search, nil, 10)
+				if err != nil {
+					t.Fatalf("SearchPastEvents(%q): %v", test.search, err)
+				}
+				if !sameTitles(events, test.want...) {
+					t.Errorf("SearchPastEvents(%q) = %q, want %q", test.search, titles(events), test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchPastEventsPagination(t *testing.T) {
+	repo := NewEventRepository(newTestDB(t), clock.Fixed(testNow))
+	talks := pastTalks()
+
+	// Two talks at the same time are ordered by ID, newest first
+	talks = append(talks, domain.Event{Title: "Same Time", Speaker: "Grace Hopper", Date: talks[1].Date})
+	added := addEvents(t, repo, talks...)
+
+	var pages [][]string
+	var after *repository.EventCursor
+	for {
+		events, err := repo.SearchPastEvents(context.Background(), "", after, 2)
+		if err != nil {
+			t.Fatalf("SearchPastEvents: %v", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		pages = append(pages, titles(events))
+		last := events[len(events)-1]
+		after = &repository.EventCursor{Date: last.Date, ID: last.ID}
+		if len(pages) > len(added) {
+			t.Fatal("pagination doesn't end")
+		}
+	}
+
+	want := [][]string{
+		{"Transformers Explained", "Same Time"},
+		{"Vector Databases", "Agents in Production"},
+		{"Prompt Engineering", "Fine-tuning 100% Offline"},
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("got pages %q, want %q", pages, want)
+	}
+	for i := range want {
+		if len(pages[i]) != len(want[i]) || pages[i][0] != want[i][0] || pages[i][1] != want[i][1] {
+			t.Fatalf("got pages %q, want %q", pages, want)
+		}
+	}
+}
+
+func TestUpcomingAndPastEvents(t *testing.T) {
+	dbManager := newTestDB(t)
+	addEvents(t, NewEventRepository(dbManager, clock.System{}),
+		domain.Event{Title: "Last Week", Speaker: "Ada Lovelace", Date: testNow.AddDate(0, 0, -7)},
+		domain.Event{Title: "Running Now", Speaker: "Alan Turing", Date: testNow.Add(-30 * time.Minute)},
+		domain.Event{Title: "Next Week", Speaker: "Grace Hopper", Date: testNow.AddDate(0, 0, 7)},
+		domain.Event{Title: "Tomorrow", Speaker: "Ada Lovelace", Date: testNow.AddDate(0, 0, 1)},
+		domain.Event{Title: "Unclaimed Slot", Date: testNow.AddDate(0, 0, -14), Open: true},
+	)
+
+	tests := []struct {
+		now      time.Time
+		upcoming []string
+		past     []string
+	}{
+		{
+			now:      testNow,
+			upcoming: []string{"Running Now", "Tomorrow", "Next Week"},
+			past:     []string{"Last Week"},
+		},
+		{
+			// Events are past once they ended, not once they started
+			now:      testNow.Add(31 * time.Minute),
+			upcoming: []string{"Tomorrow", "Next Week"},
+			past:     []string{"Running Now", "Last Week"},
+		},
+		{
+			now:      testNow.AddDate(0, 1, 0),
+			upcoming: nil,
+			past:     []string{"Next Week", "Tomorrow", "Running Now", "Last Week"},
+		},
+	}
+
+	for _, test := range tests {
+		repo := NewEventRepository(dbManager, clock.Fixed(test.now))
+
+		upcoming, err := repo.GetUpcomingEvents(context.Background())
+		if err != nil {
+			t.Fatalf("GetUpcomingEvents: %v", err)
+		}
+		if !sameTitles(upcoming, test.upcoming...) {
+			t.Errorf("at %s: upcoming = %q, want %q", test.now, titles(upcoming), test.upcoming)
+		}
+
+		past, err := repo.GetPastEvents(context.Background())
+		if err != nil {
+			t.Fatalf("GetPastEvents: %v", err)
+		}
+		if !sameTitles(past, test.past...) {
+			t.Errorf("at %s: past = %q, want %q", test.now, titles(past), test.past)
+		}
+	}
+}
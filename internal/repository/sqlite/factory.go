@@ -1,6 +1,9 @@
 package sqlite
 
 import (
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
 )
 
@@ -11,12 +14,19 @@ type RepositoryFactory struct {
 	timerRepository    *TimerRepository
 	noteRepository     *NoteRepository
 	questionRepository *QuestionRepository
+	agendaRepository   *AgendaRepository
+	timerLogRepository *TimerLogRepository
+	seriesRepository   *SeriesRepository
+	speakerRepository  *SpeakerRepository
+	resourceRepository *ResourceRepository
 }
 
-// NewRepositoryFactory creates a new repository factory
-func NewRepositoryFactory(dbPath string) (*RepositoryFactory, error) {
+// NewRepositoryFactory creates a new repository factory.
+// The clock decides which events are upcoming and which are past, and timeZone is the
+// group's timezone that events stored without one are scheduled in.
+func NewRepositoryFactory(dbPath string, clock clock.Clock, timeZone *time.Location) (*RepositoryFactory, error) {
 	// Create database manager
-	dbManager, err := NewDBManager(dbPath)
+	dbManager, err := NewDBManager(dbPath, timeZone)
 	if err != nil {
 		return nil, err
 	}
@@ -24,10 +34,15 @@ func NewRepositoryFactory(dbPath string) (*RepositoryFactory, error) {
 	// Create repositories
 	factory := &RepositoryFactory{
 		dbManager:          dbManager,
-		eventRepository:    NewEventRepository(dbManager),
+		eventRepository:    NewEventRepository(dbManager, clock),
 		timerRepository:    NewTimerRepository(dbManager),
 		noteRepository:     NewNoteRepository(dbManager),
 		questionRepository: NewQuestionRepository(dbManager),
+		agendaRepository:   NewAgendaRepository(dbManager),
+		timerLogRepository: NewTimerLogRepository(dbManager),
+		seriesRepository:   NewSeriesRepository(dbManager),
+		speakerRepository:  NewSpeakerRepository(dbManager),
+		resourceRepository: NewResourceRepository(dbManager),
 	}
 
 	return factory, nil
@@ -53,6 +68,46 @@ func (f *RepositoryFactory) GetQuestionRepository() repository.QuestionRepositor
 	return f.questionRepository
 }
 
+// GetAgendaRepository returns the agenda repository
+func (f *RepositoryFactory) GetAgendaRepository() repository.AgendaRepository {
+	return f.agendaRepository
+}
+
+// GetTimerLogRepository returns the timer log repository
+func (f *RepositoryFactory) GetTimerLogRepository() repository.TimerLogRepository {
+	return f.timerLogRepository
+}
+
+// GetSeriesRepository returns the series repository
+func (f *RepositoryFactory) GetSeriesRepository() repository.SeriesRepository {
+	return f.seriesRepository
+}
+
+// GetSpeakerRepository returns the speaker repository
+func (f *RepositoryFactory) GetSpeakerRepository() repository.SpeakerRepository {
+	return f.speakerRepository
+}
+
+// GetResourceRepository returns the resource repository
+func (f *RepositoryFactory) GetResourceRepository() repository.ResourceRepository {
+	return f.resourceRepository
+}
+
+// Repositories returns all repositories of the factory
+func (f *RepositoryFactory) Repositories() repository.Repositories {
+	return repository.Repositories{
+		Events:    f.eventRepository,
+		Speakers:  f.speakerRepository,
+		Resources: f.resourceRepository,
+		Series:    f.seriesRepository,
+		Timers:    f.timerRepository,
+		TimerLogs: f.timerLogRepository,
+		Agendas:   f.agendaRepository,
+		Notes:     f.noteRepository,
+		Questions: f.questionRepository,
+	}
+}
+
 // Close closes all repositories and the database connection
 func (f *RepositoryFactory) Close() error {
 	return f.dbManager.Close()
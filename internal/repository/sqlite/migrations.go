@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"gorm.io/gorm"
+)
+
+// Data migrations that AutoMigrate can't express. Each one checks whether it
+// still needs to run, so they are safe to run on every start.
+
+// migrateTimerState derives the timer state from the is_running column used
+// before timers had an explicit state, then drops that column
+func (m *DBManager) migrateTimerState() error {
+	migrator := m.db.Migrator()
+	if !migrator.HasColumn(&TimerModel{}, "is_running") {
+		return nil
+	}
+
+	log.Println("Migrating timers from is_running to state...")
+
+	err := m.db.Exec(`UPDATE timers SET state = CASE
+		WHEN is_running THEN 'running'
+		WHEN remaining_time <= 0 THEN 'expired'
+		WHEN remaining_time < duration THEN 'paused'
+		ELSE 'idle'
+	END`).Error
+	if err != nil {
+		return fmt.Errorf("failed to derive timer state: %w", err)
+	}
+
+	if err := migrator.DropColumn(&TimerModel{}, "is_running"); err != nil {
+		return fmt.Errorf("failed to drop is_running column: %w", err)
+	}
+
+	// SQLite drops columns by recreating the table, which loses its indexes
+	if err := migrator.AutoMigrate(&TimerModel{}); err != nil {
+		return fmt.Errorf("failed to recreate timer indexes: %w", err)
+	}
+
+	return nil
+}
+
+// migrateQuestionState derives the question state from the answered column used
+// before questions were moderated, then drops that column
+func (m *DBManager) migrateQuestionState() error {
+	migrator := m.db.Migrator()
+	if !migrator.HasColumn(&QuestionModel{}, "answered") {
+		return nil
+	}
+
+	log.Println("Migrating questions from answered to state...")
+
+	err := m.db.Exec(`UPDATE questions SET state = CASE
+		WHEN answered THEN 'answered'
+		ELSE 'pending'
+	END`).Error
+	if err != nil {
+		return fmt.Errorf("failed to derive question state: %w", err)
+	}
+
+	if err := migrator.DropColumn(&QuestionModel{}, "answered"); err != nil {
+		return fmt.Errorf("failed to drop answered column: %w", err)
+	}
+
+	// SQLite drops columns by recreating the table, which loses its indexes
+	if err := migrator.AutoMigrate(&QuestionModel{}); err != nil {
+		return fmt.Errorf("failed to recreate question indexes: %w", err)
+	}
+
+	return nil
+}
+
+// migrateEventUpcoming drops the is_upcoming column, which was set once when an event
+// was added and never updated. Upcoming and past events are now told apart by their date.
+func (m *DBManager) migrateEventUpcoming() error {
+	migrator := m.db.Migrator()
+	if !migrator.HasColumn(&EventModel{}, "is_upcoming") {
+		return nil
+	}
+
+	log.Println("Dropping events.is_upcoming in favor of the event date...")
+
+	if err := migrator.DropColumn(&EventModel{}, "is_upcoming"); err != nil {
+		return fmt.Errorf("failed to drop is_upcoming column: %w", err)
+	}
+
+	// SQLite drops columns by recreating the table, which loses its indexes
+	if err := migrator.AutoMigrate(&EventModel{}); err != nil {
+		return fmt.Errorf("failed to recreate event indexes: %w", err)
+	}
+
+	return nil
+}
+
+// migrateEventTimeZone schedules events stored without a timezone in the group's timezone.
+// Their dates were parsed from the event form without a location and stored as UTC, so the
+// stored wall clock time is the one that was typed in and is kept in the group's timezone.
+func (m *DBManager) migrateEventTimeZone() error {
+	var models []EventModel
+	if err := m.db.Unscoped().Where("time_zone = ''").Find(&models).Error; err != nil {
+		return fmt.Errorf("failed to get events without timezone: %w", err)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	log.Printf("Scheduling %d events without a timezone in %s...\n", len(models), m.timeZone)
+
+	for _, model := range models {
+		wall := model.Date.UTC()
+		date := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), m.timeZone)
+		err := m.db.Unscoped().Model(&EventModel{}).Where("id = ?", model.ID).UpdateColumns(map[string]interface{}{
+			"date":      date.UTC(),
+			"time_zone": m.timeZone.String(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to set timezone of event %d: %w", model.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateEventEndsAt stores when events that were added before events had a duration end.
+// They take the default length.
+func (m *DBManager) migrateEventEndsAt() error {
+	var models []EventModel
+	if err := m.db.Unscoped().Where("ends_at IS NULL").Find(&models).Error; err != nil {
+		return fmt.Errorf("failed to get events without end: %w", err)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	log.Printf("Storing the end of %d events...\n", len(models))
+
+	for _, model := range models {
+		endsAt := model.Date.Add(domain.EventLength).UTC()
+		err := m.db.Unscoped().Model(&EventModel{}).Where("id = ?", model.ID).UpdateColumn("ends_at", endsAt).Error
+		if err != nil {
+			return fmt.Errorf("failed to set end of event %d: %w", model.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateEventSpeakers links the events stored before speakers were their own records to
+// the speakers named in their speaker line, adding the speakers. Co-presenters separated by
+// commas or ampersands become separate speakers.
+func (m *DBManager) migrateEventSpeakers() error {
+	// Speaker names are unique ignoring case, which GORM tags can't express
+	if err := m.db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_speakers_name ON speakers(name COLLATE NOCASE)").Error; err != nil {
+		return fmt.Errorf("failed to create speaker name index: %w", err)
+	}
+
+	var models []EventModel
+	err := m.db.Unscoped().
+		Where("speaker != '' AND id NOT IN (SELECT event_id FROM event_speakers)").
+		Find(&models).Error
+	if err != nil {
+		return fmt.Errorf("failed to get events without speakers: %w", err)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	log.Printf("Linking %d events to their speakers...\n", len(models))
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range models {
+			speakers, err := findOrAddSpeakers(tx, domain.ParseSpeakers(model.Speaker))
+			if err != nil {
+				return err
+			}
+			if err := setEventSpeakers(tx, model.ID, speakers); err != nil {
+				return err
+			}
+			if err := updateSpeakerLine(tx, model.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
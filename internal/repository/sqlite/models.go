@@ -12,8 +12,23 @@ type EventModel struct {
 	Title       string
 	Speaker     string
 	Description string
-	Date        time.Time
-	IsUpcoming  bool
+	Date        time.Time `gorm:"index"`
+	// TimeZone is the IANA name of the timezone the event is scheduled in, empty for events
+	// added before timezones were stored
+	TimeZone string `gorm:"not null;default:''"`
+	// Sequence counts the updates of the event
+	Sequence int `gorm:"not null;default:0"`
+	// UID is the iCalendar UID of an imported event
+	UID string `gorm:"index"`
+	// Duration is stored in nanoseconds, 0 for events of the default length
+	Duration int64 `gorm:"not null;default:0"`
+	// EndsAt is stored next to the date so upcoming and past events are told apart in SQL
+	EndsAt time.Time `gorm:"index"`
+	// SeriesID and Occurrence are set on the events a series generated, and are unique
+	// together so a series generates each slot only once
+	SeriesID   *uint      `gorm:"uniqueIndex:idx_events_series_occurrence"`
+	Occurrence *time.Time `gorm:"uniqueIndex:idx_events_series_occurrence"`
+	Open       bool       `gorm:"not null;default:false"`
 }
 
 // TableName sets the table name for EventModel
@@ -21,13 +36,77 @@ func (EventModel) TableName() string {
 	return "events"
 }
 
+// SpeakerModel is the GORM model for speakers.
+// Names are unique ignoring case, enforced by an index the speaker migration creates.
+type SpeakerModel struct {
+	gorm.Model
+	Name      string
+	Bio       string
+	Links     string // one URL per line
+	AvatarURL string
+}
+
+// EventSpeakerModel links an event to one of its speakers, at the position the speaker is named
+type EventSpeakerModel struct {
+	EventID   uint `gorm:"primaryKey;autoIncrement:false"`
+	SpeakerID uint `gorm:"primaryKey;autoIncrement:false;index"`
+	Position  int
+}
+
+// ResourceModel is the GORM model for the resources attached to events
+type ResourceModel struct {
+	gorm.Model
+	EventID uint `gorm:"not null;index"`
+	Kind    string
+	Title   string
+	URL     string
+	Notes   string
+}
+
+// SeriesModel is the GORM model for recurring session series
+type SeriesModel struct {
+	gorm.Model
+	Title    string
+	First    time.Time
+	TimeZone string
+	Interval int   `gorm:"not null;default:1"`
+	Duration int64 // stored in nanoseconds
+	Until    *time.Time
+}
+
+// TableName sets the table name for SpeakerModel
+func (SpeakerModel) TableName() string {
+	return "speakers"
+}
+
+// TableName sets the table name for EventSpeakerModel
+func (EventSpeakerModel) TableName() string {
+	return "event_speakers"
+}
+
+// TableName sets the table name for ResourceModel
+func (ResourceModel) TableName() string {
+	return "resources"
+}
+
+// TableName sets the table name for SeriesModel
+func (SeriesModel) TableName() string {
+	return "series"
+}
+
 // TimerModel is the GORM model for timers
 type TimerModel struct {
 	gorm.Model
-	Duration      int64 // stored in nanoseconds
-	RemainingTime int64 // stored in nanoseconds
-	IsRunning     bool
+	EventID       uint   `gorm:"not null;default:0;uniqueIndex:idx_timers_event_segment"`
+	Segment       string `gorm:"not null;default:'';uniqueIndex:idx_timers_event_segment"`
+	Duration      int64  // stored in nanoseconds
+	RemainingTime int64  // stored in nanoseconds
+	Overtime      int64  `gorm:"not null;default:0"` // stored in nanoseconds
+	State         string `gorm:"not null;default:'idle'"`
 	LastStartedAt time.Time
+	// WarningThresholds are stored as comma separated seconds, largest first
+	WarningThresholds string `gorm:"not null;default:'300,60'"`
+	Version           int64  `gorm:"not null;default:0"`
 }
 
 // TableName sets the table name for TimerModel
@@ -35,11 +114,61 @@ func (TimerModel) TableName() string {
 	return "timers"
 }
 
-// NoteModel is the GORM model for notes
+// AgendaModel is the GORM model for event agendas
+type AgendaModel struct {
+	gorm.Model
+	EventID        uint `gorm:"uniqueIndex"`
+	CurrentSegment int
+	AutoAdvance    bool
+	Segments       []AgendaSegmentModel `gorm:"foreignKey:AgendaID"`
+}
+
+// TableName sets the table name for AgendaModel
+func (AgendaModel) TableName() string {
+	return "agendas"
+}
+
+// AgendaSegmentModel is the GORM model for agenda segments
+type AgendaSegmentModel struct {
+	gorm.Model
+	AgendaID uint `gorm:"index"`
+	Position int
+	Name     string
+	Duration int64 // stored in nanoseconds
+}
+
+// TableName sets the table name for AgendaSegmentModel
+func (AgendaSegmentModel) TableName() string {
+	return "agenda_segments"
+}
+
+// TimerLogModel is the GORM model for timer log entries
+type TimerLogModel struct {
+	gorm.Model
+	TimerID       uint `gorm:"index"`
+	EventID       uint `gorm:"index"`
+	Segment       string
+	Type          string
+	Duration      int64 // stored in nanoseconds
+	RemainingTime int64 // stored in nanoseconds
+	Overtime      int64 // stored in nanoseconds
+	OccurredAt    time.Time
+}
+
+// TableName sets the table name for TimerLogModel
+func (TimerLogModel) TableName() string {
+	return "timer_log"
+}
+
+// NoteModel is the GORM model for notes.
+// Notes are scoped to an event and a speaker; notes stored before that became
+// the general notes of event 0 and no speaker, shown next to the default timer.
 type NoteModel struct {
 	gorm.Model
+	EventID    uint   `gorm:"not null;default:0;index:idx_notes_scope"`
+	Speaker    string `gorm:"not null;default:'';index:idx_notes_scope"`
 	Content    string
-	PageNumber int
+	PageNumber int `gorm:"index:idx_notes_scope"`
 	TotalPages int
 }
 
@@ -51,13 +180,28 @@ func (NoteModel) TableName() string {
 // QuestionModel is the GORM model for questions
 type QuestionModel struct {
 	gorm.Model
+	EventID     uint `gorm:"not null;default:0;index"` // questions asked before events were tracked have event 0
 	Name        string
 	Content     string
 	SubmittedAt time.Time
-	Answered    bool
+	State       string `gorm:"not null;default:'pending';index"`
+	MergedInto  uint   `gorm:"not null;default:0"`
+	Votes       int    `gorm:"not null;default:0"` // kept in sync with the question_votes table
 }
 
 // TableName sets the table name for QuestionModel
 func (QuestionModel) TableName() string {
 	return "questions"
 }
+
+// QuestionVoteModel is the GORM model for question upvotes, one per question and voter
+type QuestionVoteModel struct {
+	gorm.Model
+	QuestionID uint   `gorm:"not null;uniqueIndex:idx_question_votes_voter"`
+	VoterID    string `gorm:"not null;uniqueIndex:idx_question_votes_voter;index"`
+}
+
+// TableName sets the table name for QuestionVoteModel
+func (QuestionVoteModel) TableName() string {
+	return "question_votes"
+}
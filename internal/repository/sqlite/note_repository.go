@@ -25,26 +25,28 @@ func NewNoteRepository(dbManager *DBManager) *NoteRepository {
 	}
 }
 
-// GetNote returns a note for a specific page
-func (r *NoteRepository) GetNote(ctx context.Context, pageNumber int) (domain.Note, error) {
+// GetNote returns the note for a specific page of an event and speaker
+func (r *NoteRepository) GetNote(ctx context.Context, eventID uint, speaker string, pageNumber int) (domain.Note, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return domain.Note{}, ctx.Err()
 	}
 
 	var model NoteModel
-	result := r.db.WithContext(ctx).Where("page_number = ?", pageNumber).First(&model)
+	result := r.db.WithContext(ctx).Scopes(noteScope(eventID, speaker)).Where("page_number = ?", pageNumber).First(&model)
 
 	// If no note exists for this page, create an empty one
 	if result.Error == gorm.ErrRecordNotFound {
 		// Get total pages
 		var totalPages int64
-		if err := r.db.WithContext(ctx).Model(&NoteModel{}).Count(&totalPages).Error; err != nil {
+		if err := r.db.WithContext(ctx).Model(&NoteModel{}).Scopes(noteScope(eventID, speaker)).Select("COALESCE(MAX(MAX(total_pages, page_number)), 0)").Scan(&totalPages).Error; err != nil {
 			return domain.Note{}, fmt.Errorf("failed to count total pages: %w", err)
 		}
 
 		// Return an empty note with the correct page number
 		return domain.Note{
+			EventID:    eventID,
+			Speaker:    speaker,
 			PageNumber: pageNumber,
 			TotalPages: int(totalPages),
 		}, nil
@@ -63,11 +65,13 @@ func (r *NoteRepository) SaveNote(ctx context.Context, note domain.Note) (bool,
 	}
 
 	var model NoteModel
-	result := r.db.WithContext(ctx).Where("page_number = ?", note.PageNumber).First(&model)
+	result := r.db.WithContext(ctx).Scopes(noteScope(note.EventID, note.Speaker)).Where("page_number = ?", note.PageNumber).First(&model)
 
 	// If no note exists for this page, create a new one
 	if result.Error == gorm.ErrRecordNotFound {
 		model = NoteModel{
+			EventID:    note.EventID,
+			Speaker:    note.Speaker,
 			Content:    note.Content,
 			PageNumber: note.PageNumber,
 			TotalPages: note.TotalPages,
@@ -77,7 +81,7 @@ func (r *NoteRepository) SaveNote(ctx context.Context, note domain.Note) (bool,
 			return false, fmt.Errorf("failed to create note: %w", err)
 		}
 
-		return true, nil
+		return true, r.updateTotalPages(ctx, note)
 	} else if result.Error != nil {
 		return false, fmt.Errorf("failed to get note: %w", result.Error)
 	}
@@ -90,7 +94,28 @@ func (r *NoteRepository) SaveNote(ctx context.Context, note domain.Note) (bool,
 		return false, fmt.Errorf("failed to update note: %w", err)
 	}
 
-	return true, nil
+	return true, r.updateTotalPages(ctx, note)
+}
+
+// updateTotalPages raises the page count of the other notes of the same event and speaker
+// to that of note, so pages added later are counted everywhere
+func (r *NoteRepository) updateTotalPages(ctx context.Context, note domain.Note) error {
+	err := r.db.WithContext(ctx).Model(&NoteModel{}).
+		Scopes(noteScope(note.EventID, note.Speaker)).
+		Where("total_pages < ?", note.TotalPages).
+		Update("total_pages", note.TotalPages).Error
+	if err != nil {
+		return fmt.Errorf("failed to update total pages: %w", err)
+	}
+
+	return nil
+}
+
+// noteScope limits a query to the notes of an event and speaker
+func noteScope(eventID uint, speaker string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("event_id = ? AND speaker = ?", eventID, speaker)
+	}
 }
 
 // Helper functions for conversion between domain and model
@@ -99,6 +124,8 @@ func (r *NoteRepository) SaveNote(ctx context.Context, note domain.Note) (bool,
 func convertNoteModelToDomain(model NoteModel) domain.Note {
 	return domain.Note{
 		ID:         model.Model.ID,
+		EventID:    model.EventID,
+		Speaker:    model.Speaker,
 		Content:    model.Content,
 		PageNumber: model.PageNumber,
 		TotalPages: model.TotalPages,
@@ -113,6 +140,8 @@ func convertDomainToNoteModel(note domain.Note) NoteModel {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		EventID:    note.EventID,
+		Speaker:    note.Speaker,
 		Content:    note.Content,
 		PageNumber: note.PageNumber,
 		TotalPages: note.TotalPages,
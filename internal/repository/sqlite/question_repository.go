@@ -8,6 +8,7 @@ import (
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // QuestionRepository implements the repository.QuestionRepository interface using GORM
@@ -25,15 +26,15 @@ func NewQuestionRepository(dbManager *DBManager) *QuestionRepository {
 	}
 }
 
-// GetQuestions returns all questions
-func (r *QuestionRepository) GetQuestions(ctx context.Context) ([]domain.Question, error) {
+// GetQuestions returns the questions of an event, newest first
+func (r *QuestionRepository) GetQuestions(ctx context.Context, eventID uint) ([]domain.Question, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
 	var models []QuestionModel
-	if err := r.db.WithContext(ctx).Order("submitted_at desc").Find(&models).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("submitted_at desc").Find(&models).Error; err != nil {
 		return nil, fmt.Errorf("failed to get questions: %w", err)
 	}
 
@@ -58,8 +59,10 @@ func (r *QuestionRepository) AddQuestion(ctx context.Context, question domain.Qu
 		question.SubmittedAt = time.Now()
 	}
 	
-	// Ensure question is not marked as answered when first added
-	question.Answered = false
+	// New questions always start in the queue without votes
+	question.State = domain.QuestionStatePending
+	question.MergedInto = 0
+	question.Votes = 0
 
 	// Convert domain entity to model
 	model := convertDomainToQuestionModel(question)
@@ -73,7 +76,25 @@ func (r *QuestionRepository) AddQuestion(ctx context.Context, question domain.Qu
 	return convertQuestionModelToDomain(model), nil
 }
 
-// MarkAsAnswered marks a question as answered
+// GetQuestion returns a question by ID
+func (r *QuestionRepository) GetQuestion(ctx context.Context, id uint) (domain.Question, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Question{}, ctx.Err()
+	}
+
+	var model QuestionModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Question{}, fmt.Errorf("question %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Question{}, fmt.Errorf("failed to get question: %w", result.Error)
+	}
+
+	return convertQuestionModelToDomain(model), nil
+}
+
+// MarkAsAnswered marks a pending or pinned question as answered
 func (r *QuestionRepository) MarkAsAnswered(ctx context.Context, id uint) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -81,7 +102,9 @@ func (r *QuestionRepository) MarkAsAnswered(ctx context.Context, id uint) (bool,
 	}
 
 	// Update the question
-	result := r.db.WithContext(ctx).Model(&QuestionModel{}).Where("id = ?", id).Update("answered", true)
+	result := r.db.WithContext(ctx).Model(&QuestionModel{}).
+		Where("id = ? AND state IN ?", id, []string{string(domain.QuestionStatePending), string(domain.QuestionStatePinned)}).
+		Update("state", string(domain.QuestionStateAnswered))
 	if result.Error != nil {
 		return false, fmt.Errorf("failed to mark question as answered: %w", result.Error)
 	}
@@ -90,16 +113,231 @@ func (r *QuestionRepository) MarkAsAnswered(ctx context.Context, id uint) (bool,
 	return result.RowsAffected > 0, nil
 }
 
+// UpdateQuestion saves the name and content of a question
+func (r *QuestionRepository) UpdateQuestion(ctx context.Context, question domain.Question) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Model(&QuestionModel{}).Where("id = ?", question.ID).Updates(map[string]interface{}{
+		"name":    question.Name,
+		"content": question.Content,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update question: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SetQuestionState moves a question from one moderation state to another if nobody else moved it since it was read
+func (r *QuestionRepository) SetQuestionState(ctx context.Context, id uint, from domain.QuestionState, to domain.QuestionState) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Model(&QuestionModel{}).
+		Where("id = ? AND state = ?", id, string(from)).
+		Update("state", string(to))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update question state: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Nothing was updated, either because the question is gone or because its state moved on
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&QuestionModel{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check question: %w", err)
+	}
+	if count > 0 {
+		return false, fmt.Errorf("question %d is no longer %s: %w", id, from, repository.ErrConflict)
+	}
+
+	return false, nil
+}
+
+// PinQuestion pins a question as the one being answered now and marks the question pinned before it answered
+func (r *QuestionRepository) PinQuestion(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	pinned := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model QuestionModel
+		result := tx.First(&model, id)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		} else if result.Error != nil {
+			return fmt.Errorf("failed to get question: %w", result.Error)
+		}
+
+		// Only one question per event is answered at a time
+		err := tx.Model(&QuestionModel{}).
+			Where("event_id = ? AND state = ? AND id <> ?", model.EventID, string(domain.QuestionStatePinned), id).
+			Update("state", string(domain.QuestionStateAnswered)).Error
+		if err != nil {
+			return fmt.Errorf("failed to answer pinned question: %w", err)
+		}
+
+		if err := tx.Model(&model).Update("state", string(domain.QuestionStatePinned)).Error; err != nil {
+			return fmt.Errorf("failed to pin question: %w", err)
+		}
+
+		pinned = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return pinned, nil
+}
+
+// MergeQuestions folds the source question into the target question and moves its votes
+func (r *QuestionRepository) MergeQuestions(ctx context.Context, sourceID uint, targetID uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	merged := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&QuestionModel{}).Where("id = ?", sourceID).Updates(map[string]interface{}{
+			"state":       string(domain.QuestionStateMerged),
+			"merged_into": targetID,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge question: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var voterIDs []string
+		if err := tx.Model(&QuestionVoteModel{}).Where("question_id = ?", sourceID).Pluck("voter_id", &voterIDs).Error; err != nil {
+			return fmt.Errorf("failed to get votes: %w", err)
+		}
+
+		// The unique index on question and voter skips voters who voted for both
+		for _, voterID := range voterIDs {
+			vote := QuestionVoteModel{QuestionID: targetID, VoterID: voterID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote).Error; err != nil {
+				return fmt.Errorf("failed to move vote: %w", err)
+			}
+		}
+
+		count := tx.Model(&QuestionVoteModel{}).Select("count(*)").Where("question_id = ?", targetID)
+		if err := tx.Model(&QuestionModel{}).Where("id = ?", targetID).Update("votes", count).Error; err != nil {
+			return fmt.Errorf("failed to count votes: %w", err)
+		}
+
+		merged = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return merged, nil
+}
+
+// GetRankedQuestions returns the questions of an event with the most votes first, then the oldest first
+func (r *QuestionRepository) GetRankedQuestions(ctx context.Context, eventID uint) ([]domain.Question, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []QuestionModel
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("votes desc, submitted_at asc, id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ranked questions: %w", err)
+	}
+
+	// Convert models to domain entities
+	questions := make([]domain.Question, len(models))
+	for i, model := range models {
+		questions[i] = convertQuestionModelToDomain(model)
+	}
+
+	return questions, nil
+}
+
+// Upvote adds the vote of a voter to a question
+func (r *QuestionRepository) Upvote(ctx context.Context, questionID uint, voterID string) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	voted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&QuestionModel{}).Where("id = ?", questionID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check question: %w", err)
+		}
+		if count == 0 {
+			return nil
+		}
+
+		// The unique index on question and voter keeps a voter from voting twice
+		vote := QuestionVoteModel{QuestionID: questionID, VoterID: voterID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote)
+		if result.Error != nil {
+			return fmt.Errorf("failed to add vote: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("vote of %q for question %d: %w", voterID, questionID, repository.ErrAlreadyExists)
+		}
+
+		if err := tx.Model(&QuestionModel{}).Where("id = ?", questionID).Update("votes", gorm.Expr("votes + 1")).Error; err != nil {
+			return fmt.Errorf("failed to count vote: %w", err)
+		}
+
+		voted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return voted, nil
+}
+
+// GetVotedQuestionIDs returns the IDs of the questions a voter voted for
+func (r *QuestionRepository) GetVotedQuestionIDs(ctx context.Context, voterID string) ([]uint, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	ids := make([]uint, 0)
+	if err := r.db.WithContext(ctx).Model(&QuestionVoteModel{}).Where("voter_id = ?", voterID).Pluck("question_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+
+	return ids, nil
+}
+
 // Helper functions for conversion between domain and model
 
 // convertQuestionModelToDomain converts a QuestionModel to a domain.Question
 func convertQuestionModelToDomain(model QuestionModel) domain.Question {
 	return domain.Question{
 		ID:          model.Model.ID,
+		EventID:     model.EventID,
 		Name:        model.Name,
 		Content:     model.Content,
 		SubmittedAt: model.SubmittedAt,
-		Answered:    model.Answered,
+		State:       domain.QuestionState(model.State),
+		MergedInto:  model.MergedInto,
+		Votes:       model.Votes,
 	}
 }
 
@@ -111,9 +349,12 @@ func convertDomainToQuestionModel(question domain.Question) QuestionModel {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		EventID:     question.EventID,
 		Name:        question.Name,
 		Content:     question.Content,
 		SubmittedAt: question.SubmittedAt,
-		Answered:    question.Answered,
+		State:       string(question.State),
+		MergedInto:  question.MergedInto,
+		Votes:       question.Votes,
 	}
 } 
\ No newline at end of file
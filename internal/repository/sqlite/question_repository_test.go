@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// addQuestions adds questions with the given contents to an event and returns them with their IDs
+func addQuestions(t *testing.T, repo *QuestionRepository, eventID uint, contents ...string) []domain.Question {
+	t.Helper()
+
+	added := make([]domain.Question, len(contents))
+	for i, content := range contents {
+		var err error
+		added[i], err = repo.AddQuestion(context.Background(), domain.Question{EventID: eventID, Content: content})
+		if err != nil {
+			t.Fatalf("AddQuestion failed: %v", err)
+		}
+	}
+	return added
+}
+
+// upvote votes for a question and fails the test unless the vote was counted
+func upvote(t *testing.T, repo *QuestionRepository, questionID uint, voterID string) {
+	t.Helper()
+
+	if voted, err := repo.Upvote(context.Background(), questionID, voterID); err != nil || !voted {
+		t.Fatalf("Upvote(%d, %q) = %v, %v, want true, nil", questionID, voterID, voted, err)
+	}
+}
+
+func TestUpvoteTwice(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuestionRepository(newTestDB(t))
+	question := addQuestions(t, repo, 1, "How does it work?")[0]
+
+	upvote(t, repo, question.ID, "alice")
+
+	voted, err := repo.Upvote(ctx, question.ID, "alice")
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("second Upvote = %v, %v, want ErrAlreadyExists", voted, err)
+	}
+
+	stored, err := repo.GetQuestion(ctx, question.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if stored.Votes != 1 {
+		t.Errorf("Votes = %d, want 1", stored.Votes)
+	}
+}
+
+func TestUpvoteMissingQuestion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuestionRepository(newTestDB(t))
+
+	voted, err := repo.Upvote(ctx, 42, "alice")
+	if err != nil || voted {
+		t.Fatalf("Upvote of a missing question = %v, %v, want false, nil", voted, err)
+	}
+
+	ids, err := repo.GetVotedQuestionIDs(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetVotedQuestionIDs failed: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("voted question IDs = %v, want none", ids)
+	}
+}
+
+func TestMergeQuestionsMovesVotes(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuestionRepository(newTestDB(t))
+	questions := addQuestions(t, repo, 1, "Is it fast?", "How fast is it?")
+	source, target := questions[0], questions[1]
+
+	// alice voted for both questions, bob only for the duplicate and carol only for the target
+	upvote(t, repo, source.ID, "alice")
+	upvote(t, repo, source.ID, "bob")
+	upvote(t, repo, target.ID, "alice")
+	upvote(t, repo, target.ID, "carol")
+
+	merged, err := repo.MergeQuestions(ctx, source.ID, target.ID)
+	if err != nil || !merged {
+		t.Fatalf("MergeQuestions = %v, %v, want true, nil", merged, err)
+	}
+
+	stored, err := repo.GetQuestion(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if stored.Votes != 3 {
+		t.Errorf("target Votes = %d, want 3", stored.Votes)
+	}
+
+	duplicate, err := repo.GetQuestion(ctx, source.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if duplicate.State != domain.QuestionStateMerged || duplicate.MergedInto != target.ID {
+		t.Errorf("source is %q into %d, want %q into %d", duplicate.State, duplicate.MergedInto, domain.QuestionStateMerged, target.ID)
+	}
+
+	for _, voterID := range []string{"alice", "bob", "carol"} {
+		ids, err := repo.GetVotedQuestionIDs(ctx, voterID)
+		if err != nil {
+			t.Fatalf("GetVotedQuestionIDs failed: %v", err)
+		}
+		if !slices.Contains(ids, target.ID) {
+			t.Errorf("votes of %q = %v, want a vote for question %d", voterID, ids, target.ID)
+		}
+	}
+
+	// Moved votes still keep voters from voting twice
+	if voted, err := repo.Upvote(ctx, target.ID, "bob"); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("Upvote after merge = %v, %v, want ErrAlreadyExists", voted, err)
+	}
+}
+
+func TestSetQuestionStateConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := NewQuestionRepository(newTestDB(t))
+	questions := addQuestions(t, repo, 1, "Is it fast?", "How fast is it?")
+
+	// One host merges the question while another one hides it as it was read before the merge
+	if merged, err := repo.MergeQuestions(ctx, questions[0].ID, questions[1].ID); err != nil || !merged {
+		t.Fatalf("MergeQuestions = %v, %v, want true, nil", merged, err)
+	}
+	updated, err := repo.SetQuestionState(ctx, questions[0].ID, domain.QuestionStatePending, domain.QuestionStateHidden)
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("SetQuestionState of a merged question = %v, %v, want ErrConflict", updated, err)
+	}
+
+	stored, err := repo.GetQuestion(ctx, questions[0].ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if stored.State != domain.QuestionStateMerged || stored.MergedInto != questions[1].ID {
+		t.Errorf("question is %q into %d, want %q into %d", stored.State, stored.MergedInto, domain.QuestionStateMerged, questions[1].ID)
+	}
+
+	// A question still in the state it was read in moves on
+	if updated, err := repo.SetQuestionState(ctx, questions[1].ID, domain.QuestionStatePending, domain.QuestionStateHidden); err != nil || !updated {
+		t.Fatalf("SetQuestionState = %v, %v, want true, nil", updated, err)
+	}
+	if updated, err := repo.SetQuestionState(ctx, 42, domain.QuestionStatePending, domain.QuestionStateHidden); err != nil || updated {
+		t.Fatalf("SetQuestionState of a missing question = %v, %v, want false, nil", updated, err)
+	}
+}
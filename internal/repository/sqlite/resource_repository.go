@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ResourceRepository implements the repository.ResourceRepository interface using GORM
+type ResourceRepository struct {
+	db *gorm.DB
+}
+
+// Ensure ResourceRepository implements repository.ResourceRepository
+var _ repository.ResourceRepository = &ResourceRepository{}
+
+// NewResourceRepository creates a new resource repository
+func NewResourceRepository(dbManager *DBManager) *ResourceRepository {
+	return &ResourceRepository{
+		db: dbManager.GetDB(),
+	}
+}
+
+// GetEventResources returns the resources of an event in the order they were added
+func (r *ResourceRepository) GetEventResources(ctx context.Context, eventID uint) ([]domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []ResourceModel
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event resources: %w", err)
+	}
+
+	// Convert models to domain entities
+	resources := make([]domain.Resource, len(models))
+	for i, model := range models {
+		resources[i] = convertResourceModelToDomain(model)
+	}
+
+	return resources, nil
+}
+
+// GetResource returns a resource by ID
+func (r *ResourceRepository) GetResource(ctx context.Context, id uint) (domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Resource{}, ctx.Err()
+	}
+
+	var model ResourceModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Resource{}, fmt.Errorf("resource %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Resource{}, fmt.Errorf("failed to get resource: %w", result.Error)
+	}
+
+	return convertResourceModelToDomain(model), nil
+}
+
+// AddResource adds a new resource and returns it with an ID
+func (r *ResourceRepository) AddResource(ctx context.Context, resource domain.Resource) (domain.Resource, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Resource{}, ctx.Err()
+	}
+
+	model := convertDomainToResourceModel(resource)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.Resource{}, fmt.Errorf("failed to add resource: %w", err)
+	}
+
+	return convertResourceModelToDomain(model), nil
+}
+
+// DeleteResource soft deletes a resource by setting its DeletedAt
+func (r *ResourceRepository) DeleteResource(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Delete(&ResourceModel{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete resource: %w", result.Error)
+	}
+
+	// Check if any rows were affected
+	return result.RowsAffected > 0, nil
+}
+
+// convertResourceModelToDomain converts a ResourceModel to a domain.Resource
+func convertResourceModelToDomain(model ResourceModel) domain.Resource {
+	return domain.Resource{
+		ID:      model.Model.ID,
+		EventID: model.EventID,
+		Kind:    domain.ResourceKind(model.Kind),
+		Title:   model.Title,
+		URL:     model.URL,
+		Notes:   model.Notes,
+		AddedAt: model.CreatedAt,
+	}
+}
+
+// convertDomainToResourceModel converts a domain.Resource to a ResourceModel
+func convertDomainToResourceModel(resource domain.Resource) ResourceModel {
+	return ResourceModel{
+		Model: gorm.Model{
+			ID: resource.ID,
+		},
+		EventID: resource.EventID,
+		Kind:    string(resource.Kind),
+		Title:   resource.Title,
+		URL:     resource.URL,
+		Notes:   resource.Notes,
+	}
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// eventSearchTriggers keep the events_fts index in sync with the events table.
+// Soft deletes are updates, so deleted events stay indexed and are filtered out by the queries.
+var eventSearchTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS events_fts_insert AFTER INSERT ON events BEGIN
+		INSERT INTO events_fts(rowid, title, speaker, description) VALUES (new.id, new.title, new.speaker, new.description);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS events_fts_delete AFTER DELETE ON events BEGIN
+		INSERT INTO events_fts(events_fts, rowid, title, speaker, description) VALUES ('delete', old.id, old.title, old.speaker, old.description);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS events_fts_update AFTER UPDATE ON events BEGIN
+		INSERT INTO events_fts(events_fts, rowid, title, speaker, description) VALUES ('delete', old.id, old.title, old.speaker, old.description);
+		INSERT INTO events_fts(rowid, title, speaker, description) VALUES (new.id, new.title, new.speaker, new.description);
+	END`,
+}
+
+// setupEventSearch creates the FTS5 index over event titles, speakers and descriptions
+// and reports whether full-text search is available.
+// FTS5 is only compiled into go-sqlite3 with the sqlite_fts5 build tag. Without it the
+// triggers are removed, as they would make every write to events fail, and searches
+// fall back to LIKE matching.
+func (m *DBManager) setupEventSearch() (bool, error) {
+	var available bool
+	if err := m.db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&available).Error; err != nil {
+		return false, fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	if !available {
+		log.Println("SQLite was built without FTS5, searching events with LIKE (build with -tags sqlite_fts5 for full-text search)")
+		for _, trigger := range []string{"events_fts_insert", "events_fts_delete", "events_fts_update"} {
+			if err := m.db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+				return false, fmt.Errorf("failed to drop event search trigger: %w", err)
+			}
+		}
+		return false, nil
+	}
+
+	err := m.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(
+		title, speaker, description, content='events', content_rowid='id'
+	)`).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to create event search index: %w", err)
+	}
+
+	// Migrations that recreate the events table drop its triggers, so they are created on every start
+	for _, trigger := range eventSearchTriggers {
+		if err := m.db.Exec(trigger).Error; err != nil {
+			return false, fmt.Errorf("failed to create event search trigger: %w", err)
+		}
+	}
+
+	// Rebuilding picks up events written while the triggers were missing; the archive is small
+	if err := m.db.Exec(`INSERT INTO events_fts(events_fts) VALUES ('rebuild')`).Error; err != nil {
+		return false, fmt.Errorf("failed to rebuild event search index: %w", err)
+	}
+
+	return true, nil
+}
+
+// ftsQuery turns the words of a search into an FTS5 query matching events that contain
+// every word, each as a prefix. Words are quoted so FTS5 syntax is taken literally.
+func ftsQuery(words []string) string {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// likePattern returns a LIKE pattern matching text that contains word, with
+// the LIKE wildcards in word escaped by a backslash
+func likePattern(word string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(word)
+	return "%" + escaped + "%"
+}
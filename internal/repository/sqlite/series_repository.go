@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SeriesRepository implements the repository.SeriesRepository interface using GORM
+type SeriesRepository struct {
+	db *gorm.DB
+}
+
+// Ensure SeriesRepository implements repository.SeriesRepository
+var _ repository.SeriesRepository = &SeriesRepository{}
+
+// NewSeriesRepository creates a new series repository
+func NewSeriesRepository(dbManager *DBManager) *SeriesRepository {
+	return &SeriesRepository{
+		db: dbManager.GetDB(),
+	}
+}
+
+// GetAllSeries returns all series, oldest first
+func (r *SeriesRepository) GetAllSeries(ctx context.Context) ([]domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []SeriesModel
+	if err := r.db.WithContext(ctx).Order("id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	// Convert models to domain entities
+	series := make([]domain.Series, len(models))
+	for i, model := range models {
+		series[i] = convertSeriesModelToDomain(model)
+	}
+
+	return series, nil
+}
+
+// GetSeries returns a series by ID
+func (r *SeriesRepository) GetSeries(ctx context.Context, id uint) (domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Series{}, ctx.Err()
+	}
+
+	var model SeriesModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Series{}, fmt.Errorf("series %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Series{}, fmt.Errorf("failed to get series: %w", result.Error)
+	}
+
+	return convertSeriesModelToDomain(model), nil
+}
+
+// AddSeries adds a new series and returns it with an ID
+func (r *SeriesRepository) AddSeries(ctx context.Context, series domain.Series) (domain.Series, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Series{}, ctx.Err()
+	}
+
+	model := convertDomainToSeriesModel(series)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.Series{}, fmt.Errorf("failed to add series: %w", err)
+	}
+
+	return convertSeriesModelToDomain(model), nil
+}
+
+// DeleteSeries soft deletes a series by setting its DeletedAt
+func (r *SeriesRepository) DeleteSeries(ctx context.Context, id uint) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	result := r.db.WithContext(ctx).Delete(&SeriesModel{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete series: %w", result.Error)
+	}
+
+	// Check if any rows were affected
+	return result.RowsAffected > 0, nil
+}
+
+// convertSeriesModelToDomain converts a SeriesModel to a domain.Series
+func convertSeriesModelToDomain(model SeriesModel) domain.Series {
+	var until time.Time
+	if model.Until != nil {
+		until = *model.Until
+	}
+
+	return domain.Series{
+		ID:       model.Model.ID,
+		Title:    model.Title,
+		First:    model.First,
+		TimeZone: model.TimeZone,
+		Interval: model.Interval,
+		Duration: time.Duration(model.Duration),
+		Until:    until,
+	}
+}
+
+// convertDomainToSeriesModel converts a domain.Series to a SeriesModel
+func convertDomainToSeriesModel(series domain.Series) SeriesModel {
+	var until *time.Time
+	if !series.Until.IsZero() {
+		untilUTC := series.Until.UTC()
+		until = &untilUTC
+	}
+
+	return SeriesModel{
+		Model: gorm.Model{
+			ID: series.ID,
+		},
+		Title:    series.Title,
+		First:    series.First.UTC(),
+		TimeZone: series.TimeZone,
+		Interval: series.Interval,
+		Duration: int64(series.Duration),
+		Until:    until,
+	}
+}
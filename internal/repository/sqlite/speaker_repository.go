@@ -0,0 +1,278 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// SpeakerRepository implements the repository.SpeakerRepository interface using GORM
+type SpeakerRepository struct {
+	db *gorm.DB
+}
+
+// Ensure SpeakerRepository implements repository.SpeakerRepository
+var _ repository.SpeakerRepository = &SpeakerRepository{}
+
+// NewSpeakerRepository creates a new speaker repository
+func NewSpeakerRepository(dbManager *DBManager) *SpeakerRepository {
+	return &SpeakerRepository{
+		db: dbManager.GetDB(),
+	}
+}
+
+// GetAllSpeakers returns all speakers ordered by name
+func (r *SpeakerRepository) GetAllSpeakers(ctx context.Context) ([]domain.Speaker, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []SpeakerModel
+	if err := r.db.WithContext(ctx).Order("name COLLATE NOCASE asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get speakers: %w", err)
+	}
+
+	return convertSpeakerModelsToDomain(models), nil
+}
+
+// GetSpeaker returns a speaker by ID
+func (r *SpeakerRepository) GetSpeaker(ctx context.Context, id uint) (domain.Speaker, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Speaker{}, ctx.Err()
+	}
+
+	var model SpeakerModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Speaker{}, fmt.Errorf("speaker %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Speaker{}, fmt.Errorf("failed to get speaker: %w", result.Error)
+	}
+
+	return convertSpeakerModelToDomain(model), nil
+}
+
+// SearchSpeakers returns up to limit speakers whose name contains search, ordered by name
+func (r *SpeakerRepository) SearchSpeakers(ctx context.Context, search string, limit int) ([]domain.Speaker, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []SpeakerModel
+	err := r.db.WithContext(ctx).
+		Where(`name LIKE ? ESCAPE '\'`, likePattern(strings.TrimSpace(search))).
+		Order("name COLLATE NOCASE asc").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search speakers: %w", err)
+	}
+
+	return convertSpeakerModelsToDomain(models), nil
+}
+
+// UpdateSpeaker updates the profile of a speaker and the speaker line of their events
+func (r *SpeakerRepository) UpdateSpeaker(ctx context.Context, speaker domain.Speaker) (bool, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	updated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		if err := tx.Model(&SpeakerModel{}).Where("name = ? COLLATE NOCASE AND id != ?", speaker.Name, speaker.ID).Count(&taken).Error; err != nil {
+			return fmt.Errorf("failed to check speaker name: %w", err)
+		}
+		if taken > 0 {
+			return fmt.Errorf("speaker %q: %w", speaker.Name, repository.ErrConflict)
+		}
+
+		model := convertDomainToSpeakerModel(speaker)
+		result := tx.Model(&SpeakerModel{}).Where("id = ?", speaker.ID).Updates(map[string]interface{}{
+			"name":       model.Name,
+			"bio":        model.Bio,
+			"links":      model.Links,
+			"avatar_url": model.AvatarURL,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update speaker: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+
+		// The speaker lines of the speaker's events carry the name
+		var eventIDs []uint
+		if err := tx.Model(&EventSpeakerModel{}).Where("speaker_id = ?", speaker.ID).Pluck("event_id", &eventIDs).Error; err != nil {
+			return fmt.Errorf("failed to get speaker events: %w", err)
+		}
+		for _, eventID := range eventIDs {
+			if err := updateSpeakerLine(tx, eventID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// findOrAddSpeakers returns the stored speakers named like speakers, ignoring case, in the same
+// order, adding the ones that don't exist yet
+func findOrAddSpeakers(tx *gorm.DB, speakers []domain.Speaker) ([]SpeakerModel, error) {
+	models := make([]SpeakerModel, 0, len(speakers))
+	for _, speaker := range speakers {
+		var model SpeakerModel
+		result := tx.Where("name = ? COLLATE NOCASE", speaker.Name).Limit(1).Find(&model)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to find speaker %q: %w", speaker.Name, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			model = SpeakerModel{Name: speaker.Name}
+			if err := tx.Create(&model).Error; err != nil {
+				return nil, fmt.Errorf("failed to add speaker %q: %w", speaker.Name, err)
+			}
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// setEventSpeakers replaces the speakers an event is linked to
+func setEventSpeakers(tx *gorm.DB, eventID uint, speakers []SpeakerModel) error {
+	if err := tx.Where("event_id = ?", eventID).Delete(&EventSpeakerModel{}).Error; err != nil {
+		return fmt.Errorf("failed to unlink speakers of event %d: %w", eventID, err)
+	}
+	if len(speakers) == 0 {
+		return nil
+	}
+
+	links := make([]EventSpeakerModel, len(speakers))
+	for i, speaker := range speakers {
+		links[i] = EventSpeakerModel{EventID: eventID, SpeakerID: speaker.ID, Position: i}
+	}
+	if err := tx.Create(&links).Error; err != nil {
+		return fmt.Errorf("failed to link speakers of event %d: %w", eventID, err)
+	}
+	return nil
+}
+
+// updateSpeakerLine sets the speaker line of an event to the names of the speakers it is linked to
+func updateSpeakerLine(tx *gorm.DB, eventID uint) error {
+	var names []string
+	err := tx.Model(&EventSpeakerModel{}).
+		Joins("JOIN speakers ON speakers.id = event_speakers.speaker_id").
+		Where("event_speakers.event_id = ?", eventID).
+		Order("event_speakers.position").
+		Pluck("speakers.name", &names).Error
+	if err != nil {
+		return fmt.Errorf("failed to get speaker names of event %d: %w", eventID, err)
+	}
+
+	line := domain.SpeakerLine(domain.SpeakersNamed(names))
+	if err := tx.Unscoped().Model(&EventModel{}).Where("id = ?", eventID).UpdateColumn("speaker", line).Error; err != nil {
+		return fmt.Errorf("failed to update speaker line of event %d: %w", eventID, err)
+	}
+	return nil
+}
+
+// loadEventSpeakers sets the Speakers of events to the speakers they are linked to
+func loadEventSpeakers(db *gorm.DB, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uint, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var links []EventSpeakerModel
+	if err := db.Where("event_id IN ?", eventIDs).Order("event_id, position").Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to get event speakers: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	speakerIDs := make([]uint, len(links))
+	for i, link := range links {
+		speakerIDs[i] = link.SpeakerID
+	}
+	var models []SpeakerModel
+	if err := db.Where("id IN ?", speakerIDs).Find(&models).Error; err != nil {
+		return fmt.Errorf("failed to get speakers: %w", err)
+	}
+	speakers := make(map[uint]domain.Speaker, len(models))
+	for _, model := range models {
+		speakers[model.ID] = convertSpeakerModelToDomain(model)
+	}
+
+	byEvent := make(map[uint][]domain.Speaker)
+	for _, link := range links {
+		if speaker, ok := speakers[link.SpeakerID]; ok {
+			byEvent[link.EventID] = append(byEvent[link.EventID], speaker)
+		}
+	}
+	for i := range events {
+		events[i].Speakers = byEvent[events[i].ID]
+	}
+	return nil
+}
+
+// speakerLine returns the speaker line of an event given by speakers
+func speakerLine(speakers []SpeakerModel) string {
+	return domain.SpeakerLine(convertSpeakerModelsToDomain(speakers))
+}
+
+// convertSpeakerModelToDomain converts a SpeakerModel to a domain.Speaker
+func convertSpeakerModelToDomain(model SpeakerModel) domain.Speaker {
+	var links []string
+	for _, link := range strings.Split(model.Links, "\n") {
+		if link = strings.TrimSpace(link); link != "" {
+			links = append(links, link)
+		}
+	}
+
+	return domain.Speaker{
+		ID:        model.Model.ID,
+		Name:      model.Name,
+		Bio:       model.Bio,
+		Links:     links,
+		AvatarURL: model.AvatarURL,
+	}
+}
+
+// convertSpeakerModelsToDomain converts SpeakerModels to domain.Speakers
+func convertSpeakerModelsToDomain(models []SpeakerModel) []domain.Speaker {
+	speakers := make([]domain.Speaker, len(models))
+	for i, model := range models {
+		speakers[i] = convertSpeakerModelToDomain(model)
+	}
+	return speakers
+}
+
+// convertDomainToSpeakerModel converts a domain.Speaker to a SpeakerModel
+func convertDomainToSpeakerModel(speaker domain.Speaker) SpeakerModel {
+	return SpeakerModel{
+		Model: gorm.Model{
+			ID: speaker.ID,
+		},
+		Name:      speaker.Name,
+		Bio:       speaker.Bio,
+		Links:     strings.Join(speaker.Links, "\n"),
+		AvatarURL: speaker.AvatarURL,
+	}
+}
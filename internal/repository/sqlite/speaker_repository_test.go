@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/clock"
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+)
+
+// speakerNames returns the names of speakers
+func speakerNames(speakers []domain.Speaker) []string {
+	names := make([]string, len(speakers))
+	for i, speaker := range speakers {
+		names[i] = speaker.Name
+	}
+	return names
+}
+
+func TestAddEventMatchesSpeakers(t *testing.T) {
+	ctx := context.Background()
+	dbManager := newTestDB(t)
+	eventRepo := NewEventRepository(dbManager, clock.Fixed(testNow))
+	speakerRepo := NewSpeakerRepository(dbManager)
+
+	added := addEvents(t, eventRepo,
+		domain.Event{Title: "Transformers Explained", Speaker: "Ada Lovelace & Alan Turing", Date: testNow.AddDate(0, 0, 7)},
+		// The same speaker in other case and spacing, named twice, next to a new one
+		domain.Event{Title: "Vector Databases", Speaker: "ada  LOVELACE, Grace Hopper, Ada Lovelace", Date: testNow.AddDate(0, 0, 14)},
+	)
+
+	if got, want := added[0].Speaker, "Ada Lovelace, Alan Turing"; got != want {
+		t.Errorf("first Speaker = %q, want %q", got, want)
+	}
+	if got, want := added[1].Speaker, "Ada Lovelace, Grace Hopper"; got != want {
+		t.Errorf("second Speaker = %q, want %q", got, want)
+	}
+	if len(added[1].Speakers) != 2 || added[1].Speakers[0].ID != added[0].Speakers[0].ID {
+		t.Fatalf("second Speakers = %+v, want Ada Lovelace linked by ID %d first", added[1].Speakers, added[0].Speakers[0].ID)
+	}
+
+	speakers, err := speakerRepo.GetAllSpeakers(ctx)
+	if err != nil {
+		t.Fatalf("GetAllSpeakers failed: %v", err)
+	}
+	if got := speakerNames(speakers); len(got) != 3 || got[0] != "Ada Lovelace" || got[1] != "Alan Turing" || got[2] != "Grace Hopper" {
+		t.Errorf("speakers = %q, want Ada Lovelace, Alan Turing and Grace Hopper", got)
+	}
+
+	events, err := eventRepo.GetSpeakerEvents(ctx, added[0].Speakers[0].ID)
+	if err != nil {
+		t.Fatalf("GetSpeakerEvents failed: %v", err)
+	}
+	if !sameTitles(events, "Transformers Explained", "Vector Databases") {
+		t.Errorf("events of Ada Lovelace = %q, want both talks", titles(events))
+	}
+}
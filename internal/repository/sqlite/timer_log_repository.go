@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TimerLogRepository implements the repository.TimerLogRepository interface using GORM
+type TimerLogRepository struct {
+	db *gorm.DB
+}
+
+// Ensure TimerLogRepository implements repository.TimerLogRepository
+var _ repository.TimerLogRepository = &TimerLogRepository{}
+
+// NewTimerLogRepository creates a new timer log repository
+func NewTimerLogRepository(dbManager *DBManager) *TimerLogRepository {
+	return &TimerLogRepository{
+		db: dbManager.GetDB(),
+	}
+}
+
+// AddTimerLogEntry appends an entry to the timer log and returns it with an ID
+func (r *TimerLogRepository) AddTimerLogEntry(ctx context.Context, entry domain.TimerLogEntry) (domain.TimerLogEntry, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.TimerLogEntry{}, ctx.Err()
+	}
+
+	model := convertDomainToTimerLogModel(entry)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return domain.TimerLogEntry{}, fmt.Errorf("failed to add timer log entry: %w", err)
+	}
+
+	return convertTimerLogModelToDomain(model), nil
+}
+
+// GetEventTimerLog returns the log entries of all timers of an event in the order they happened
+func (r *TimerLogRepository) GetEventTimerLog(ctx context.Context, eventID uint) ([]domain.TimerLogEntry, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []TimerLogModel
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("occurred_at asc, id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get timer log: %w", err)
+	}
+
+	entries := make([]domain.TimerLogEntry, len(models))
+	for i, model := range models {
+		entries[i] = convertTimerLogModelToDomain(model)
+	}
+
+	return entries, nil
+}
+
+// Helper functions for conversion between domain and model
+
+// convertTimerLogModelToDomain converts a TimerLogModel to a domain.TimerLogEntry
+func convertTimerLogModelToDomain(model TimerLogModel) domain.TimerLogEntry {
+	return domain.TimerLogEntry{
+		ID:            model.Model.ID,
+		TimerID:       model.TimerID,
+		EventID:       model.EventID,
+		Segment:       model.Segment,
+		Type:          domain.TimerEventType(model.Type),
+		Duration:      time.Duration(model.Duration),
+		RemainingTime: time.Duration(model.RemainingTime),
+		Overtime:      time.Duration(model.Overtime),
+		OccurredAt:    model.OccurredAt,
+	}
+}
+
+// convertDomainToTimerLogModel converts a domain.TimerLogEntry to a TimerLogModel
+func convertDomainToTimerLogModel(entry domain.TimerLogEntry) TimerLogModel {
+	return TimerLogModel{
+		Model: gorm.Model{
+			ID:        entry.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		TimerID:       entry.TimerID,
+		EventID:       entry.EventID,
+		Segment:       entry.Segment,
+		Type:          string(entry.Type),
+		Duration:      entry.Duration.Nanoseconds(),
+		RemainingTime: entry.RemainingTime.Nanoseconds(),
+		Overtime:      entry.Overtime.Nanoseconds(),
+		OccurredAt:    entry.OccurredAt,
+	}
+}
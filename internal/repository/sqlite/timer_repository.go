@@ -3,11 +3,15 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-go-golems/ai-in-action-app/internal/domain"
 	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TimerRepository implements the repository.TimerRepository interface using GORM
@@ -25,7 +29,7 @@ func NewTimerRepository(dbManager *DBManager) *TimerRepository {
 	}
 }
 
-// GetTimer returns the current timer
+// GetTimer returns the default timer
 func (r *TimerRepository) GetTimer(ctx context.Context) (domain.Timer, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -33,80 +37,89 @@ func (r *TimerRepository) GetTimer(ctx context.Context) (domain.Timer, error) {
 	}
 
 	var model TimerModel
-	result := r.db.WithContext(ctx).First(&model)
+	result := r.db.WithContext(ctx).Where("event_id = ? AND segment = ?", 0, "").First(&model)
 
 	// If no timer exists, create a default one
 	if result.Error == gorm.ErrRecordNotFound {
-		defaultTimer := domain.Timer{
-			Duration:      15 * time.Minute,
-			RemainingTime: 15 * time.Minute,
-			IsRunning:     false,
-		}
+		defaultTimer := domain.NewTimer(15 * time.Minute)
 
 		// Save the default timer
 		newTimer, err := r.createTimer(ctx, defaultTimer)
-		if err != nil {
-			return domain.Timer{}, fmt.Errorf("failed to create default timer: %w", err)
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			if err != nil {
+				return domain.Timer{}, fmt.Errorf("failed to create default timer: %w", err)
+			}
+			return newTimer, nil
 		}
 
-		return newTimer, nil
-	} else if result.Error != nil {
+		// Another request created the default timer meanwhile
+		result = r.db.WithContext(ctx).Where("event_id = ? AND segment = ?", 0, "").First(&model)
+	}
+	if result.Error != nil {
 		return domain.Timer{}, fmt.Errorf("failed to get timer: %w", result.Error)
 	}
 
-	// If timer is running, calculate the remaining time
-	timer := convertTimerModelToDomain(model)
-	if timer.IsRunning {
-		elapsed := time.Since(timer.LastStartedAt)
-		if elapsed < timer.RemainingTime {
-			timer.RemainingTime = timer.RemainingTime - elapsed
-
-			// Update the timer with the new remaining time and last started time
-			model.RemainingTime = timer.RemainingTime.Nanoseconds()
-			model.LastStartedAt = time.Now()
-
-			if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
-				return domain.Timer{}, fmt.Errorf("failed to update timer: %w", err)
-			}
-		} else {
-			// Timer has expired
-			timer.RemainingTime = 0
-			timer.IsRunning = false
+	return convertTimerModelToDomain(model).At(time.Now()), nil
+}
 
-			// Update the timer
-			model.RemainingTime = 0
-			model.IsRunning = false
+// GetTimerByID returns the timer with the given ID
+func (r *TimerRepository) GetTimerByID(ctx context.Context, id uint) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
+	}
 
-			if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
-				return domain.Timer{}, fmt.Errorf("failed to update timer: %w", err)
-			}
-		}
+	var model TimerModel
+	result := r.db.WithContext(ctx).First(&model, id)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Timer{}, fmt.Errorf("timer %d: %w", id, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Timer{}, fmt.Errorf("failed to get timer: %w", result.Error)
 	}
 
-	return timer, nil
+	return convertTimerModelToDomain(model).At(time.Now()), nil
 }
 
-// UpdateTimer updates the timer state
+// UpdateTimer updates the timer state if nobody else updated it since it was read
 func (r *TimerRepository) UpdateTimer(ctx context.Context, timer domain.Timer) (bool, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
 		return false, ctx.Err()
 	}
 
-	// Convert domain entity to model
-	model := convertDomainToTimerModel(timer)
-
-	// Update in database
-	result := r.db.WithContext(ctx).Save(&model)
+	// Only update the row if it still has the version the timer was read with
+	result := r.db.WithContext(ctx).Model(&TimerModel{}).
+		Where("id = ? AND version = ?", timer.ID, timer.Version).
+		Updates(map[string]interface{}{
+			"duration":           timer.Duration.Nanoseconds(),
+			"remaining_time":     timer.RemainingTime.Nanoseconds(),
+			"overtime":           timer.Overtime.Nanoseconds(),
+			"state":              string(timer.State),
+			"last_started_at":    timer.LastStartedAt,
+			"warning_thresholds": formatThresholds(timer.WarningThresholds),
+			"version":            gorm.Expr("version + 1"),
+		})
 	if result.Error != nil {
 		return false, fmt.Errorf("failed to update timer: %w", result.Error)
 	}
 
-	// Check if any rows were affected
-	return result.RowsAffected > 0, nil
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Nothing was updated, either because the timer is gone or because its version moved on
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&TimerModel{}).Where("id = ?", timer.ID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check timer: %w", err)
+	}
+	if count > 0 {
+		return false, fmt.Errorf("timer %d at version %d: %w", timer.ID, timer.Version, repository.ErrConflict)
+	}
+
+	return false, nil
 }
 
-// ResetTimer resets the timer with a new duration
+// ResetTimer resets the default timer with a new duration
 func (r *TimerRepository) ResetTimer(ctx context.Context, duration time.Duration) (domain.Timer, error) {
 	// Check if context is done
 	if ctx.Err() != nil {
@@ -114,45 +127,124 @@ func (r *TimerRepository) ResetTimer(ctx context.Context, duration time.Duration
 	}
 
 	var model TimerModel
-	result := r.db.WithContext(ctx).First(&model)
+	result := r.db.WithContext(ctx).Where("event_id = ? AND segment = ?", 0, "").First(&model)
 
 	// If no timer exists, create a new one
 	if result.Error == gorm.ErrRecordNotFound {
-		newTimer := domain.Timer{
-			Duration:      duration,
-			RemainingTime: duration,
-			IsRunning:     false,
+		timer, err := r.createTimer(ctx, domain.NewTimer(duration))
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			return timer, err
 		}
 
-		return r.createTimer(ctx, newTimer)
-	} else if result.Error != nil {
+		// Another request created the timer meanwhile, which is reset instead
+		result = r.db.WithContext(ctx).Where("event_id = ? AND segment = ?", 0, "").First(&model)
+	}
+	if result.Error != nil {
 		return domain.Timer{}, fmt.Errorf("failed to get timer: %w", result.Error)
 	}
 
 	// Reset the timer
-	model.Duration = duration.Nanoseconds()
-	model.RemainingTime = duration.Nanoseconds()
-	model.IsRunning = false
+	timer := convertTimerModelToDomain(model)
+	timer.Reset(duration)
 
-	// Save the updated timer
-	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+	// Save the updated timer, which may have been deleted since it was read
+	updated, err := r.UpdateTimer(ctx, timer)
+	if err != nil {
 		return domain.Timer{}, fmt.Errorf("failed to reset timer: %w", err)
+	} else if !updated {
+		return domain.Timer{}, fmt.Errorf("timer %d: %w", timer.ID, repository.ErrNotFound)
 	}
+	timer.Version++
 
-	return convertTimerModelToDomain(model), nil
+	return timer, nil
 }
 
-// createTimer creates a new timer
-func (r *TimerRepository) createTimer(ctx context.Context, timer domain.Timer) (domain.Timer, error) {
-	model := TimerModel{
-		Duration:      timer.Duration.Nanoseconds(),
-		RemainingTime: timer.RemainingTime.Nanoseconds(),
-		IsRunning:     timer.IsRunning,
-		LastStartedAt: time.Now(),
+// CreateEventTimer creates a timer for an event segment
+func (r *TimerRepository) CreateEventTimer(ctx context.Context, eventID uint, segment string, duration time.Duration) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
+	}
+
+	timer := domain.NewTimer(duration)
+	timer.EventID = eventID
+	timer.Segment = segment
+
+	return r.createTimer(ctx, timer)
+}
+
+// GetEventTimers returns all timers of an event ordered by creation
+func (r *TimerRepository) GetEventTimers(ctx context.Context, eventID uint) ([]domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []TimerModel
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event timers: %w", err)
+	}
+
+	now := time.Now()
+	timers := make([]domain.Timer, len(models))
+	for i, model := range models {
+		timers[i] = convertTimerModelToDomain(model).At(now)
+	}
+
+	return timers, nil
+}
+
+// GetEventTimer returns the timer of an event segment
+func (r *TimerRepository) GetEventTimer(ctx context.Context, eventID uint, segment string) (domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return domain.Timer{}, ctx.Err()
 	}
 
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
-		return domain.Timer{}, fmt.Errorf("failed to create timer: %w", err)
+	var model TimerModel
+	result := r.db.WithContext(ctx).Where("event_id = ? AND segment = ?", eventID, segment).First(&model)
+	if result.Error == gorm.ErrRecordNotFound {
+		return domain.Timer{}, fmt.Errorf("timer for event %d segment %q: %w", eventID, segment, repository.ErrNotFound)
+	} else if result.Error != nil {
+		return domain.Timer{}, fmt.Errorf("failed to get timer: %w", result.Error)
+	}
+
+	return convertTimerModelToDomain(model).At(time.Now()), nil
+}
+
+// GetRunningTimers returns the timers saved as running down with time left, ordered by ID
+func (r *TimerRepository) GetRunningTimers(ctx context.Context) ([]domain.Timer, error) {
+	// Check if context is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var models []TimerModel
+	if err := r.db.WithContext(ctx).Where("state = ?", string(domain.TimerStateRunning)).Order("id asc").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get running timers: %w", err)
+	}
+
+	now := time.Now()
+	timers := make([]domain.Timer, len(models))
+	for i, model := range models {
+		timers[i] = convertTimerModelToDomain(model).At(now)
+	}
+
+	return timers, nil
+}
+
+// createTimer creates a new timer, or returns ErrAlreadyExists if its event segment already has one
+func (r *TimerRepository) createTimer(ctx context.Context, timer domain.Timer) (domain.Timer, error) {
+	model := convertDomainToTimerModel(timer)
+	model.LastStartedAt = time.Now()
+
+	// The unique index on event and segment keeps concurrent requests from creating the same timer twice
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+	if result.Error != nil {
+		return domain.Timer{}, fmt.Errorf("failed to create timer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.Timer{}, fmt.Errorf("timer for event %d segment %q: %w", timer.EventID, timer.Segment, repository.ErrAlreadyExists)
 	}
 
 	return convertTimerModelToDomain(model), nil
@@ -163,11 +255,16 @@ func (r *TimerRepository) createTimer(ctx context.Context, timer domain.Timer) (
 // convertTimerModelToDomain converts a TimerModel to a domain.Timer
 func convertTimerModelToDomain(model TimerModel) domain.Timer {
 	return domain.Timer{
-		ID:            model.Model.ID,
-		Duration:      time.Duration(model.Duration),
-		RemainingTime: time.Duration(model.RemainingTime),
-		IsRunning:     model.IsRunning,
-		LastStartedAt: model.LastStartedAt,
+		ID:                model.Model.ID,
+		EventID:           model.EventID,
+		Segment:           model.Segment,
+		Duration:          time.Duration(model.Duration),
+		RemainingTime:     time.Duration(model.RemainingTime),
+		Overtime:          time.Duration(model.Overtime),
+		State:             domain.TimerState(model.State),
+		LastStartedAt:     model.LastStartedAt,
+		WarningThresholds: parseThresholds(model.WarningThresholds),
+		Version:           model.Version,
 	}
 }
 
@@ -179,9 +276,36 @@ func convertDomainToTimerModel(timer domain.Timer) TimerModel {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
-		Duration:      timer.Duration.Nanoseconds(),
-		RemainingTime: timer.RemainingTime.Nanoseconds(),
-		IsRunning:     timer.IsRunning,
-		LastStartedAt: timer.LastStartedAt,
+		EventID:           timer.EventID,
+		Segment:           timer.Segment,
+		Duration:          timer.Duration.Nanoseconds(),
+		RemainingTime:     timer.RemainingTime.Nanoseconds(),
+		Overtime:          timer.Overtime.Nanoseconds(),
+		State:             string(timer.State),
+		LastStartedAt:     timer.LastStartedAt,
+		WarningThresholds: formatThresholds(timer.WarningThresholds),
+		Version:           timer.Version,
+	}
+}
+
+// formatThresholds stores warning thresholds as comma separated seconds
+func formatThresholds(thresholds []time.Duration) string {
+	parts := make([]string, len(thresholds))
+	for i, threshold := range thresholds {
+		parts[i] = strconv.FormatInt(int64(threshold/time.Second), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseThresholds reads warning thresholds stored as comma separated seconds, skipping invalid entries
+func parseThresholds(value string) []time.Duration {
+	thresholds := make([]time.Duration, 0)
+	for _, part := range strings.Split(value, ",") {
+		seconds, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+		thresholds = append(thresholds, time.Duration(seconds)*time.Second)
 	}
+	return thresholds
 }
@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/ai-in-action-app/internal/domain"
+	"github.com/go-go-golems/ai-in-action-app/internal/repository"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func TestUpdateTimerVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTimerRepository(newTestDB(t))
+
+	created, err := repo.CreateEventTimer(ctx, 1, "talk", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("CreateEventTimer failed: %v", err)
+	}
+
+	// Two hosts read the same timer and both try to change it
+	first, err := repo.GetTimerByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetTimerByID failed: %v", err)
+	}
+	second := first
+
+	if err := first.Start(time.Now()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if updated, err := repo.UpdateTimer(ctx, first); err != nil || !updated {
+		t.Fatalf("UpdateTimer = %v, %v, want true, nil", updated, err)
+	}
+
+	second.Reset(5 * time.Minute)
+	updated, err := repo.UpdateTimer(ctx, second)
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("UpdateTimer of a stale timer = %v, %v, want ErrConflict", updated, err)
+	}
+
+	stored, err := repo.GetTimerByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetTimerByID failed: %v", err)
+	}
+	if !stored.IsRunning() || stored.Duration != 10*time.Minute {
+		t.Errorf("stored timer is %q for %s, want the started timer for 10m0s", stored.State, stored.Duration)
+	}
+	if stored.Version != first.Version+1 {
+		t.Errorf("Version = %d, want %d", stored.Version, first.Version+1)
+	}
+
+	// Rereading the timer picks up the new version, so the change goes through
+	stored.Reset(5 * time.Minute)
+	if updated, err := repo.UpdateTimer(ctx, stored); err != nil || !updated {
+		t.Fatalf("UpdateTimer after rereading = %v, %v, want true, nil", updated, err)
+	}
+}
+
+func TestUpdateMissingTimer(t *testing.T) {
+	repo := NewTimerRepository(newTestDB(t))
+
+	timer := domain.NewTimer(time.Minute)
+	timer.ID = 42
+	updated, err := repo.UpdateTimer(context.Background(), timer)
+	if err != nil || updated {
+		t.Errorf("UpdateTimer of a missing timer = %v, %v, want false, nil", updated, err)
+	}
+}
+
+func TestCreateEventTimerTwice(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTimerRepository(newTestDB(t))
+
+	first, err := repo.CreateEventTimer(ctx, 1, "talk", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("CreateEventTimer failed: %v", err)
+	}
+
+	// The unique index turns away the second timer for the segment, however close together they come
+	if _, err := repo.CreateEventTimer(ctx, 1, "talk", 5*time.Minute); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("second CreateEventTimer = %v, want ErrAlreadyExists", err)
+	}
+
+	stored, err := repo.GetEventTimer(ctx, 1, "talk")
+	if err != nil {
+		t.Fatalf("GetEventTimer failed: %v", err)
+	}
+	if stored.ID != first.ID || stored.Duration != 10*time.Minute {
+		t.Errorf("stored timer is %d for %s, want timer %d for 10m0s", stored.ID, stored.Duration, first.ID)
+	}
+
+	// Other segments and events get timers of their own
+	if _, err := repo.CreateEventTimer(ctx, 1, "Q&A", 5*time.Minute); err != nil {
+		t.Fatalf("CreateEventTimer of another segment failed: %v", err)
+	}
+	if _, err := repo.CreateEventTimer(ctx, 2, "talk", 5*time.Minute); err != nil {
+		t.Fatalf("CreateEventTimer of another event failed: %v", err)
+	}
+}
+
+func TestGetTimerCreatedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	dbManager := newTestDB(t)
+	repo := NewTimerRepository(dbManager)
+
+	// Another request creates the default timer right after this one found none
+	var other domain.Timer
+	err := dbManager.GetDB().Callback().Query().After("gorm:query").Register("test:create_default_timer", func(tx *gorm.DB) {
+		if other.ID != 0 || tx.Statement.Table != "timers" {
+			return
+		}
+		var err error
+		if other, err = repo.createTimer(ctx, domain.NewTimer(5*time.Minute)); err != nil {
+			t.Errorf("createTimer of the other request failed: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	timer, err := repo.GetTimer(ctx)
+	if err != nil {
+		t.Fatalf("GetTimer failed: %v", err)
+	}
+	if other.ID == 0 || timer.ID != other.ID || timer.Duration != 5*time.Minute {
+		t.Errorf("GetTimer = timer %d for %s, want the other request's timer %d for 5m0s", timer.ID, timer.Duration, other.ID)
+	}
+}
@@ -27,6 +27,30 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
     
+    // Show event dates in the viewer's timezone. The server renders them in the
+    // timezone each event is scheduled in, which stays as the fallback without JavaScript.
+    const localDateFormat = new Intl.DateTimeFormat(undefined, {
+        weekday: 'long',
+        year: 'numeric',
+        month: 'short',
+        day: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+        timeZoneName: 'short'
+    });
+    function localizeTimes(root) {
+        root.querySelectorAll('time[data-local-time]').forEach(function(el) {
+            const date = new Date(el.getAttribute('datetime'));
+            if (!isNaN(date)) {
+                el.textContent = localDateFormat.format(date);
+            }
+        });
+    }
+    localizeTimes(document);
+    document.body.addEventListener('htmx:load', function(event) {
+        localizeTimes(event.target);
+    });
+
     // Global function to close modals after HTMX requests
     window.closeModal = function(modalId) {
         const modalEl = document.getElementById(modalId || 'add-event-modal');
@@ -38,6 +62,34 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     };
     
+    // Add an empty speaker field for a co-speaker to the event and claim forms.
+    // The field is a copy of the first one, so it suggests speakers the same way.
+    window.addSpeakerField = function() {
+        const fields = document.getElementById('speaker-fields');
+        if (!fields) return;
+
+        const field = fields.querySelector('input').cloneNode(false);
+        field.id = 'speaker-' + fields.children.length;
+        field.value = '';
+        field.required = false;
+        fields.appendChild(field);
+        htmx.process(field);
+        field.focus();
+    };
+
+    // Swap in the error fragments the server sends when a client is rate limited, a form
+    // can't be saved, an upload is too large or a slot was claimed first, HTMX ignores the content of
+    // error responses by default. Those fragments always name their target with HX-Retarget,
+    // other error responses are plain messages that mustn't replace the page.
+    // The request still counts as failed, so forms keep what was typed.
+    document.body.addEventListener('htmx:beforeSwap', function(event) {
+        const status = event.detail.xhr.status;
+        if ((status === 429 || status === 422 || status === 413 || status === 409) &&
+            event.detail.xhr.getResponseHeader('HX-Retarget')) {
+            event.detail.shouldSwap = true;
+        }
+    });
+
     // Listen for successful form submissions via HTMX
     document.body.addEventListener('htmx:afterRequest', function(event) {
         // If the request was successful and came from a form inside a modal